@@ -0,0 +1,327 @@
+package goyai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// androidLegacyLanguages maps deprecated ISO 639 language codes, still used by Android resource qualifiers, to current ones.
+var androidLegacyLanguages = map[string]string{"in": "id", "iw": "he", "ji": "yi"}
+
+// androidLocale infers the locale of an Android string resource file from its directory "values[-<qualifiers>]"
+// (e.g. "res/values-vi/strings.xml", "values-pt-rBR/strings.xml", "values-b+sr+Latn/strings.xml"). It returns "" for
+// the default resources ("values/strings.xml") and files not in a "values" directory, and false if the qualifiers of
+// the directory are not only a locale (e.g. "values-night", "values-vi-land"), in which case the file should be ignored.
+func androidLocale(relPath string) (string, bool) {
+	dir := path.Base(path.Dir(relPath))
+	if dir == "values" || !strings.HasPrefix(dir, "values-") {
+		return "", true
+	}
+	qualifiers := strings.Split(dir, "-")[1:]
+	// mobile country code and mobile network code qualifiers precede the locale qualifier
+	for len(qualifiers) > 0 && (strings.HasPrefix(qualifiers[0], "mcc") || strings.HasPrefix(qualifiers[0], "mnc")) {
+		qualifiers = qualifiers[1:]
+	}
+	if len(qualifiers) == 0 {
+		return "", false
+	}
+	locale := qualifiers[0]
+	if strings.HasPrefix(locale, "b+") {
+		// BCP 47 qualifier (e.g. "b+sr+Latn")
+		locale = strings.ReplaceAll(locale[2:], "+", "-")
+		qualifiers = qualifiers[1:]
+	} else {
+		if lang, ok := androidLegacyLanguages[locale]; ok {
+			locale = lang
+		}
+		qualifiers = qualifiers[1:]
+		if len(qualifiers) > 0 && len(qualifiers[0]) == 3 && qualifiers[0][0] == 'r' {
+			// region qualifier (e.g. "rBR")
+			locale += "-" + qualifiers[0][1:]
+			qualifiers = qualifiers[1:]
+		}
+	}
+	if len(qualifiers) > 0 || !isFileNameLocale(locale) {
+		return "", false
+	}
+	return locale, true
+}
+
+type androidItem struct {
+	Quantity string `xml:"quantity,attr"`
+	Text     string `xml:",innerxml"`
+}
+
+type androidResource struct {
+	Name      string        `xml:"name,attr"`
+	Formatted string        `xml:"formatted,attr"`
+	Text      string        `xml:",innerxml"`
+	Items     []androidItem `xml:"item"`
+}
+
+var reAndroidXliffTag = regexp.MustCompile(`</?xliff:g[^>]*>`)
+
+// androidText converts the raw content of a string resource to a message template: XML entities and CDATA sections are
+// decoded, <xliff:g> tags are removed (other markup, e.g. "<b>", is kept as-is), Android escape sequences, double quotes
+// and whitespaces are processed as by aapt, and format specifiers are converted to positional placeholders (unless the
+// resource is not formatted).
+func androidText(raw string, formatted bool) string {
+	raw = reAndroidXliffTag.ReplaceAllString(raw, "")
+	var decoded strings.Builder
+	for raw != "" {
+		start := strings.Index(raw, "<![CDATA[")
+		if start < 0 {
+			decoded.WriteString(html.UnescapeString(raw))
+			break
+		}
+		decoded.WriteString(html.UnescapeString(raw[:start]))
+		raw = raw[start+len("<![CDATA["):]
+		end := strings.Index(raw, "]]>")
+		if end < 0 {
+			end = len(raw)
+		}
+		decoded.WriteString(raw[:end])
+		raw = strings.TrimPrefix(raw[end:], "]]>")
+	}
+	text := escapeTemplateBraces(androidUnescape(decoded.String()))
+	if formatted {
+		text = convertPrintfFormat(text, reAndroidFormatSpec)
+	}
+	return text
+}
+
+// androidUnescape processes escape sequences (e.g. "\n", "\'", "\uXXXX"), double quotes and whitespaces of an Android
+// string resource: whitespaces outside double quotes are collapsed into a single space and trimmed, double quotes
+// themselves are removed.
+func androidUnescape(s string) string {
+	var sb strings.Builder
+	quoted, pendingSpace := false, false
+	write := func(str string) {
+		if pendingSpace {
+			sb.WriteByte(' ')
+			pendingSpace = false
+		}
+		sb.WriteString(str)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				write("\n")
+			case 't':
+				write("\t")
+			case 'u':
+				if i+5 <= len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						write(string(rune(r)))
+						i += 4
+						continue
+					}
+				}
+				write("u")
+			default:
+				write(s[i : i+1])
+			}
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			pendingSpace = sb.Len() > 0
+		default:
+			write(s[i : i+1])
+		}
+	}
+	return sb.String()
+}
+
+// androidEscape escapes a string to be the content of an Android string resource.
+func androidEscape(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\'':
+			sb.WriteString(`\'`)
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case i == 0 && (r == '@' || r == '?'):
+			sb.WriteString(`\` + string(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	result := sb.String()
+	if strings.TrimSpace(result) != result || strings.Contains(result, "  ") {
+		// double quotes preserve whitespaces
+		result = `"` + result + `"`
+	}
+	return xmlEscape(result)
+}
+
+// loadLangFileAndroid loads messages of a locale from an Android string resource file (strings.xml).
+//
+// Each <string> is a message, each <plurals> is a message whose <item>s are plural forms, and each <string-array> is a
+// list of messages "<name>[0]", "<name>[1]"... A comment preceding a resource is the message's description.
+func loadLangFileAndroid(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing Android strings file: locale is not specified")
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(buf))
+	msgMap := make(map[string]interface{})
+	description, depth := "", 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error parsing Android strings file: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+			if depth == 1 {
+				description = strings.TrimSpace(string(t))
+			}
+		case xml.EndElement:
+			depth--
+		case xml.StartElement:
+			if depth == 0 {
+				if t.Name.Local != "resources" {
+					return fmt.Errorf("error parsing Android strings file: unexpected root element <%s>", t.Name.Local)
+				}
+				depth++
+				continue
+			}
+			var res androidResource
+			if err := decoder.DecodeElement(&res, &t); err != nil {
+				return fmt.Errorf("error parsing Android strings file: %w", err)
+			}
+			formatted := res.Formatted != "false"
+			switch t.Name.Local {
+			case "string":
+				msgMap[res.Name] = androidMessageData(description, map[string]string{pluralOther: androidText(res.Text, formatted)})
+			case "plurals":
+				msgData := make(map[string]string)
+				for _, item := range res.Items {
+					if containsString(pluralCategories, item.Quantity) {
+						msgData[item.Quantity] = androidText(item.Text, formatted)
+					}
+				}
+				msgMap[res.Name] = androidMessageData(description, msgData)
+			case "string-array":
+				for i, item := range res.Items {
+					msgMap[fmt.Sprintf("%s[%d]", res.Name, i)] = androidMessageData(description, map[string]string{pluralOther: androidText(item.Text, formatted)})
+				}
+			}
+			description = ""
+		}
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+func androidMessageData(description string, msgData map[string]string) map[string]string {
+	if description != "" {
+		msgData["description"] = description
+	}
+	return msgData
+}
+
+var reAndroidArrayItem = regexp.MustCompile(`^(.+)\[(\d+)\]$`)
+
+// ExportAndroidStrings writes messages of a locale as an Android string resource file (strings.xml) to w.
+//
+// Messages with plural forms are written as <plurals>, messages "<name>[0]", "<name>[1]"... as a <string-array>, and
+// other messages as <string>. Descriptions of messages are written as comments, and placeholders are converted to
+// format specifiers with explicit argument index (e.g. "%1$s"), see LocalizeConfig.TemplateData. Message ids should be
+// valid Android resource names. Messages in ICU MessageFormat (see Message.Syntax), with select variants (see
+// Message.Variants) or with exact plural forms (see Message.Exact) cannot be exported, an error is returned if there is
+// any.
+//
+// Available since v0.3.0
+func ExportAndroidStrings(w io.Writer, i18n I18n, locale string) error {
+	_, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	if err := checkNoIcuMessages("an Android string resource file", messages); err != nil {
+		return err
+	}
+	if err := checkNoVariants("an Android string resource file", messages); err != nil {
+		return err
+	}
+	if err := checkNoExact("an Android string resource file", messages); err != nil {
+		return err
+	}
+	// string-arrays are messages "<name>[0]"..."<name>[n-1]" without gaps
+	arrays := make(map[string][]*Message)
+	for _, msg := range messages {
+		if match := reAndroidArrayItem.FindStringSubmatch(msg.Id); match != nil {
+			index, _ := strconv.Atoi(match[2])
+			items := arrays[match[1]]
+			for len(items) <= index {
+				items = append(items, nil)
+			}
+			items[index] = msg
+			arrays[match[1]] = items
+		}
+	}
+	for name, items := range arrays {
+		for _, item := range items {
+			if item == nil {
+				delete(arrays, name)
+				break
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString("<resources>\n")
+	for _, msg := range messages {
+		name, items := msg.Id, []*Message(nil)
+		if match := reAndroidArrayItem.FindStringSubmatch(msg.Id); match != nil && arrays[match[1]] != nil {
+			if match[2] != "0" {
+				continue
+			}
+			name, items = match[1], arrays[match[1]]
+		}
+		if msg.Description != "" {
+			bw.WriteString("    <!-- " + strings.ReplaceAll(msg.Description, "--", "- -") + " -->\n")
+		}
+		switch {
+		case items != nil:
+			bw.WriteString(`    <string-array name="` + xmlEscape(name) + `">` + "\n")
+			for _, item := range items {
+				bw.WriteString("        <item>" + androidEscape(templateToPrintf(item.Other, "s", 0)) + "</item>\n")
+			}
+			bw.WriteString("    </string-array>\n")
+		case msg.hasPluralForms():
+			bw.WriteString(`    <plurals name="` + xmlEscape(name) + `">` + "\n")
+			for _, category := range pluralCategories {
+				if text := msg.pluralForm(category); text != "" {
+					bw.WriteString(`        <item quantity="` + category + `">` + androidEscape(templateToPrintf(text, "s", 0)) + "</item>\n")
+				}
+			}
+			bw.WriteString("    </plurals>\n")
+		default:
+			bw.WriteString(`    <string name="` + xmlEscape(name) + `">` + androidEscape(templateToPrintf(msg.Other, "s", 0)) + "</string>\n")
+		}
+	}
+	bw.WriteString("</resources>\n")
+	return bw.Flush()
+}
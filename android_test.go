@@ -0,0 +1,184 @@
+package goyai
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAndroidLocale(t *testing.T) {
+	testName := "TestAndroidLocale"
+	testCases := []struct {
+		relPath, locale string
+		ok              bool
+	}{
+		{"res/values/strings.xml", "", true},
+		{"strings.xml", "", true},
+		{"res/values-vi/strings.xml", "vi", true},
+		{"values-pt-rBR/strings.xml", "pt-BR", true},
+		{"values-b+sr+Latn/strings.xml", "sr-Latn", true},
+		{"values-in/strings.xml", "id", true},
+		{"values-mcc452-vi/strings.xml", "vi", true},
+		{"values-night/strings.xml", "", false},
+		{"values-vi-land/strings.xml", "", false},
+		{"values-v21/strings.xml", "", false},
+	}
+	for _, tc := range testCases {
+		locale, ok := androidLocale(tc.relPath)
+		if locale != tc.locale || ok != tc.ok {
+			t.Fatalf("%s failed for [%s]: expected (%#v, %#v) but received (%#v, %#v)", testName, tc.relPath, tc.locale, tc.ok, locale, ok)
+		}
+	}
+}
+
+const androidStringsEn = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="_name">English</string>
+    <!-- Greeting message -->
+    <string name="hello">Hello <xliff:g id="name">%1$s</xliff:g>!</string>
+    <string name="quote">Don\'t \"panic\"\n   now   </string>
+    <string name="spaces">"  keep  spaces  "</string>
+    <string name="markup"><![CDATA[<b>Bold</b>]]> &amp; more</string>
+    <string name="percent" formatted="false">100% %s</string>
+    <string name="braces">Hello %1$s, {{x}}</string>
+    <string name="braces_raw" formatted="false">{{.x}} {y}</string>
+    <plurals name="files">
+        <item quantity="one">%d file in %2$s</item>
+        <item quantity="other">%d files in %2$s</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Mercury</item>
+        <item>Venus</item>
+    </string-array>
+    <color name="primary">#FF0000</color>
+</resources>
+`
+
+const androidStringsRu = `<resources>
+    <string name="hello">Привет, %1$s!</string>
+    <plurals name="files">
+        <item quantity="one">%d файл в %2$s</item>
+        <item quantity="few">%d файла в %2$s</item>
+        <item quantity="many">%d файлов в %2$s</item>
+        <item quantity="other">%d файла в %2$s</item>
+    </plurals>
+</resources>
+`
+
+func TestBuildI18n_Android(t *testing.T) {
+	testName := "TestBuildI18n_Android"
+	fsys := fstest.MapFS{
+		"res/values/strings.xml":       {Data: []byte(androidStringsEn)},
+		"res/values-ru/strings.xml":    {Data: []byte(androidStringsRu)},
+		"res/values-night/strings.xml": {Data: []byte(`<resources><string name="hello">Good night</string></resources>`)},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "res", Recursive: true, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := "Greeting message", i18n.(*Goi18n).messagesStore["en"]["hello"].Description; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		params        []interface{}
+		expected      string
+	}{
+		{"en", "hello", []interface{}{"Thanh"}, "Hello Thanh!"},
+		{"ru", "hello", []interface{}{"Thanh"}, "Привет, Thanh!"},
+		{"en", "quote", nil, "Don't \"panic\"\n now"},
+		{"en", "spaces", nil, "  keep  spaces  "},
+		{"en", "markup", nil, "<b>Bold</b> & more"},
+		{"en", "percent", nil, "100% %s"},
+		{"en", "braces", []interface{}{"Thanh"}, "Hello Thanh, {{x}}"},
+		{"en", "braces_raw", nil, "{{.x}} {y}"},
+		{"en", "files", []interface{}{LocalizeConfig{PluralCount: 1, TemplateData: map[string]interface{}{"_0": 1, "_1": "Docs"}}}, "1 file in Docs"},
+		{"en", "files", []interface{}{LocalizeConfig{PluralCount: 5, TemplateData: map[string]interface{}{"_0": 5, "_1": "Docs"}}}, "5 files in Docs"},
+		{"ru", "files", []interface{}{LocalizeConfig{PluralCount: 3, TemplateData: map[string]interface{}{"_0": 3, "_1": "Docs"}}}, "3 файла в Docs"},
+		{"ru", "files", []interface{}{LocalizeConfig{PluralCount: 5, TemplateData: map[string]interface{}{"_0": 5, "_1": "Docs"}}}, "5 файлов в Docs"},
+		{"en", "planets[1]", nil, "Venus"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize(tc.locale, tc.msgId, tc.params...); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	i18n, err = BuildI18nFromBytes([]byte(androidStringsRu), I18nOptions{DefaultLocale: "ru"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Привет, Thanh!", i18n.Localize("ru", "hello", "Thanh"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestExportAndroidStrings(t *testing.T) {
+	testName := "TestExportAndroidStrings"
+	i18n, err := BuildI18nFromBytes([]byte(androidStringsEn), I18nOptions{I18nFileFormat: AndroidStrings, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := ExportAndroidStrings(buf, i18n, "en"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for _, expected := range []string{
+		"<!-- Greeting message -->\n    <string name=\"hello\">Hello %1$s!</string>",
+		`<string name="quote">Don\&#39;t \&#34;panic\&#34;\n now</string>`,
+		`<string name="spaces">&#34;  keep  spaces  &#34;</string>`,
+		`<item quantity="one">%1$s file in %2$s</item>`,
+		"<string-array name=\"planets\">\n        <item>Mercury</item>\n        <item>Venus</item>\n    </string-array>",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("%s failed: expected %#v in\n%s", testName, expected, buf.String())
+		}
+	}
+	exported, err := BuildI18nFromBytes(buf.Bytes(), I18nOptions{DefaultLocale: "en"})
+	if exported == nil || err != nil {
+		t.Fatalf("%s failed: %s\n%s", testName, err, buf.String())
+	}
+	for msgId, msg := range i18n.(*Goi18n).messagesStore["en"] {
+		if msgId == "percent" {
+			// "%" is not escaped in messages without placeholders
+			continue
+		}
+		for _, count := range []interface{}{nil, 1, 5} {
+			cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"_0": count, "_1": "X"}}
+			if e, v := i18n.Localize("en", msgId, cfg), exported.Localize("en", msgId, cfg); v != e {
+				t.Fatalf("%s failed for [%s/%v]: expected %#v but received %#v (%#v)", testName, msgId, count, e, v, msg)
+			}
+		}
+	}
+	if err := ExportAndroidStrings(buf, NullI18n(), "en"); err == nil {
+		t.Fatalf("%s failed: expected error for non-existing locale", testName)
+	}
+
+	icu, err := BuildI18nFromBytes([]byte("en:\n  _syntax: icu\n  items: \"{count, plural, one {# item} other {# items}}\"\n"), I18nOptions{DefaultLocale: "en"})
+	if icu == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAndroidStrings(new(bytes.Buffer), icu, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+
+	variants, err := BuildI18nFromBytes([]byte("en:\n  hello:\n    other: Hello\n    select:\n      female: Hi madam\n"), I18nOptions{DefaultLocale: "en"})
+	if variants == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAndroidStrings(new(bytes.Buffer), variants, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+
+	exact, err := BuildI18nFromBytes([]byte("en:\n  files:\n    \"=0\": No files\n    one: One file\n    other: \"{{.count}} files\"\n"), I18nOptions{DefaultLocale: "en"})
+	if exact == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAndroidStrings(new(bytes.Buffer), exact, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with exact plural forms", testName)
+	}
+}
@@ -0,0 +1,402 @@
+package goyai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// appleLocale infers the locale of an Apple .strings/.stringsdict file from its directory "<locale>.lproj"
+// (e.g. "vi.lproj/Localizable.strings", "pt-BR.lproj/Localizable.stringsdict"). It returns false if the file is not in
+// a locale directory (e.g. "Base.lproj/Localizable.strings").
+func appleLocale(relPath string) (string, bool) {
+	dir := path.Base(path.Dir(relPath))
+	if !strings.HasSuffix(dir, ".lproj") {
+		return "", false
+	}
+	if locale := strings.TrimSuffix(dir, ".lproj"); isFileNameLocale(locale) {
+		return locale, true
+	}
+	return "", false
+}
+
+// appleStringsEntry is an entry "key" = "value"; of a .strings file, with the comment preceding it.
+type appleStringsEntry struct {
+	key, value, comment string
+}
+
+// appleNoComment is the comment generated by Xcode for strings without comment.
+const appleNoComment = "No comment provided by engineer."
+
+// decodeAppleText decodes the content of a .strings file, which is UTF-8 or UTF-16 (with byte order mark) encoded.
+func decodeAppleText(buf []byte) string {
+	if len(buf) >= 2 && (buf[0] == 0xFF && buf[1] == 0xFE || buf[0] == 0xFE && buf[1] == 0xFF) {
+		littleEndian := buf[0] == 0xFF
+		units := make([]uint16, 0, len(buf)/2-1)
+		for i := 2; i+1 < len(buf); i += 2 {
+			if littleEndian {
+				units = append(units, uint16(buf[i])|uint16(buf[i+1])<<8)
+			} else {
+				units = append(units, uint16(buf[i])<<8|uint16(buf[i+1]))
+			}
+		}
+		return string(utf16.Decode(units))
+	}
+	return string(bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf")))
+}
+
+// parseAppleStrings parses the content of an Apple .strings file: entries "key" = "value"; with C-style escape
+// sequences, and /* ... */ or // comments. Unquoted keys and values (letters, digits and "_.$:/-") are accepted, as in
+// old-style property lists.
+func parseAppleStrings(buf []byte) ([]appleStringsEntry, error) {
+	s := decodeAppleText(buf)
+	pos := 0
+	errorAt := func(msg string) error {
+		return fmt.Errorf("error parsing .strings file at line %d: %s", strings.Count(s[:pos], "\n")+1, msg)
+	}
+	// skip skips whitespaces and comments, and returns the last comment
+	skip := func() string {
+		comment := ""
+		for pos < len(s) {
+			switch {
+			case strings.ContainsRune(" \t\r\n", rune(s[pos])):
+				pos++
+			case strings.HasPrefix(s[pos:], "/*"):
+				end := strings.Index(s[pos+2:], "*/")
+				if end < 0 {
+					comment, pos = strings.TrimSpace(s[pos+2:]), len(s)
+				} else {
+					comment, pos = strings.TrimSpace(s[pos+2:pos+2+end]), pos+2+end+2
+				}
+			case strings.HasPrefix(s[pos:], "//"):
+				end := strings.IndexByte(s[pos:], '\n')
+				if end < 0 {
+					end = len(s) - pos
+				}
+				comment = strings.TrimSpace(s[pos+2 : pos+end])
+				pos += end
+			default:
+				return comment
+			}
+		}
+		return comment
+	}
+	readString := func() (string, error) {
+		if pos >= len(s) {
+			return "", errorAt("unexpected end of file")
+		}
+		if s[pos] != '"' {
+			start := pos
+			for pos < len(s) && (isAlphaNum(s[pos:pos+1]) || strings.IndexByte("_.$:/-", s[pos]) >= 0) {
+				pos++
+			}
+			if pos == start {
+				return "", errorAt(fmt.Sprintf("unexpected character %q", s[pos]))
+			}
+			return s[start:pos], nil
+		}
+		var sb strings.Builder
+		for pos++; pos < len(s); pos++ {
+			c := s[pos]
+			if c == '"' {
+				pos++
+				return sb.String(), nil
+			}
+			if c != '\\' || pos+1 >= len(s) {
+				sb.WriteByte(c)
+				continue
+			}
+			pos++
+			switch s[pos] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'U', 'u':
+				if pos+5 > len(s) {
+					return "", errorAt("malformed \\UXXXX escape")
+				}
+				r, err := strconv.ParseUint(s[pos+1:pos+5], 16, 16)
+				if err != nil {
+					return "", errorAt("malformed \\UXXXX escape")
+				}
+				pos += 4
+				// surrogate pairs are encoded as two consecutive escapes
+				if r >= 0xD800 && r < 0xDC00 && pos+7 <= len(s) && (s[pos+1:pos+3] == `\U` || s[pos+1:pos+3] == `\u`) {
+					if low, err := strconv.ParseUint(s[pos+3:pos+7], 16, 16); err == nil && low >= 0xDC00 && low < 0xE000 {
+						r = 0x10000 + (r-0xD800)<<10 + (low - 0xDC00)
+						pos += 6
+					}
+				}
+				sb.WriteRune(rune(r))
+			default:
+				sb.WriteByte(s[pos])
+			}
+		}
+		return "", errorAt("unterminated string")
+	}
+
+	entries := make([]appleStringsEntry, 0)
+	for {
+		comment := skip()
+		if pos >= len(s) {
+			return entries, nil
+		}
+		key, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		if skip(); pos < len(s) && s[pos] == ';' {
+			// "key"; is a key without value
+			pos++
+			continue
+		}
+		if pos >= len(s) || s[pos] != '=' {
+			return nil, errorAt("expected '='")
+		}
+		pos++
+		skip()
+		value, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		if skip(); pos >= len(s) || s[pos] != ';' {
+			return nil, errorAt("expected ';'")
+		}
+		pos++
+		if comment == appleNoComment {
+			comment = ""
+		}
+		entries = append(entries, appleStringsEntry{key: key, value: value, comment: comment})
+	}
+}
+
+// loadLangFileAppleStrings loads messages of a locale from an Apple .strings file. Each key is a message id, the comment
+// preceding an entry is the message's description, and format specifiers (e.g. "%@", "%1$d") are converted to
+// positional placeholders.
+func loadLangFileAppleStrings(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing .strings file: locale is not specified")
+	}
+	entries, err := parseAppleStrings(buf)
+	if err != nil {
+		return err
+	}
+	msgMap := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		if entry.key == "_display" || entry.key == "_name" {
+			msgMap[entry.key] = entry.value
+			continue
+		}
+		msgData := map[string]string{pluralOther: convertPrintfFormat(escapeTemplateBraces(entry.value), reAppleFormatSpec)}
+		if entry.comment != "" {
+			msgData["description"] = entry.comment
+		}
+		msgMap[entry.key] = msgData
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+// decodePlist decodes the XML property list element start (and its children) into a map[string]interface{} (dict),
+// []interface{} (array), bool (true/false) or string (other elements).
+func decodePlist(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict", "array":
+		dict, array, key := make(map[string]interface{}), make([]interface{}, 0), ""
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.EndElement:
+				if start.Name.Local == "dict" {
+					return dict, nil
+				}
+				return array, nil
+			case xml.StartElement:
+				if start.Name.Local == "dict" && t.Name.Local == "key" {
+					if err := decoder.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				value, err := decodePlist(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				if start.Name.Local == "dict" {
+					dict[key] = value
+				} else {
+					array = append(array, value)
+				}
+			}
+		}
+	case "true", "false":
+		return start.Name.Local == "true", decoder.Skip()
+	default:
+		var value string
+		err := decoder.DecodeElement(&value, &start)
+		return value, err
+	}
+}
+
+const (
+	stringsdictFormatKey    = "NSStringLocalizedFormatKey"
+	stringsdictSpecTypeKey  = "NSStringFormatSpecTypeKey"
+	stringsdictValueTypeKey = "NSStringFormatValueTypeKey"
+	stringsdictPluralType   = "NSStringPluralRuleType"
+)
+
+var reStringsdictVariable = regexp.MustCompile(`%(?:\d+\$)?#@(\w+)@`)
+
+// loadLangFileAppleStringsdict loads messages of a locale from an Apple .stringsdict file.
+//
+// Each top level key is a message id. Plural variants of the first variable (e.g. "%#@files@") of the format
+// NSStringLocalizedFormatKey are plural forms of the message, other variables are replaced by their variant "other".
+// Format specifiers (e.g. "%d", "%1$@") are converted to positional placeholders.
+func loadLangFileAppleStringsdict(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing .stringsdict file: locale is not specified")
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(buf))
+	var root interface{}
+	for root == nil {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("error parsing .stringsdict file: %w", err)
+		}
+		if t, ok := token.(xml.StartElement); ok && t.Name.Local != "plist" {
+			if root, err = decodePlist(decoder, t); err != nil {
+				return fmt.Errorf("error parsing .stringsdict file: %w", err)
+			}
+		}
+	}
+	rootDict, ok := root.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("error parsing .stringsdict file: root element is not a dict")
+	}
+	msgMap := make(map[string]interface{}, len(rootDict))
+	for msgId, entry := range rootDict {
+		entryDict, _ := entry.(map[string]interface{})
+		format, ok := entryDict[stringsdictFormatKey].(string)
+		if !ok {
+			return fmt.Errorf("error parsing .stringsdict file: missing %s of [%s]", stringsdictFormatKey, msgId)
+		}
+		msgData := make(map[string]string)
+		for _, category := range pluralCategories {
+			found, first := category == pluralOther, true
+			text := reStringsdictVariable.ReplaceAllStringFunc(format, func(ref string) string {
+				spec, _ := entryDict[reStringsdictVariable.FindStringSubmatch(ref)[1]].(map[string]interface{})
+				variant, ok := spec[category].(string)
+				if ok && first {
+					found = true
+				} else {
+					variant, _ = spec[pluralOther].(string)
+				}
+				first = false
+				return variant
+			})
+			if found {
+				msgData[category] = convertPrintfFormat(escapeTemplateBraces(text), reAppleFormatSpec)
+			}
+		}
+		msgMap[msgId] = msgData
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+// appleEscape escapes a string to be a quoted string of a .strings file (without the quotes).
+func appleEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s)
+}
+
+// ExportAppleStrings writes messages of a locale as an Apple .strings file (UTF-8 encoded) to w.
+//
+// The plural form "other" of each message is written, descriptions of messages are written as comments, and
+// placeholders are converted to object format specifiers with explicit argument index (e.g. "%1$@"), see
+// LocalizeConfig.TemplateData. Use ExportAppleStringsdict to export plural forms of messages. Messages in ICU
+// MessageFormat (see Message.Syntax) or with select variants (see Message.Variants) cannot be exported, an error is
+// returned if there is any.
+//
+// Available since v0.3.0
+func ExportAppleStrings(w io.Writer, i18n I18n, locale string) error {
+	_, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	if err := checkNoIcuMessages("a .strings file", messages); err != nil {
+		return err
+	}
+	if err := checkNoVariants("a .strings file", messages); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for i, msg := range messages {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		if msg.Description != "" {
+			bw.WriteString("/* " + strings.ReplaceAll(msg.Description, "*/", "* /") + " */\n")
+		}
+		bw.WriteString(`"` + appleEscape(msg.Id) + `" = "` + appleEscape(templateToPrintf(msg.Other, "@", 0)) + `";` + "\n")
+	}
+	return bw.Flush()
+}
+
+// ExportAppleStringsdict writes messages of a locale that have plural forms as an Apple .stringsdict file to w.
+//
+// Each message is written with the format "%#@value@", whose variable "value" (an integer, NSStringFormatValueTypeKey
+// "d") has the plural forms of the message as variants. The plural count is argument 1 of the format, placeholders
+// are converted to object format specifiers with explicit argument index starting from 2 (e.g. "%2$@" for the first
+// param of Localize, see LocalizeConfig.TemplateData). Messages in ICU MessageFormat (see
+// Message.Syntax), with select variants (see Message.Variants) or with exact plural forms (see Message.Exact) cannot be
+// exported, an error is returned if there is any.
+//
+// Available since v0.3.0
+func ExportAppleStringsdict(w io.Writer, i18n I18n, locale string) error {
+	_, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	if err := checkNoIcuMessages("a .stringsdict file", messages); err != nil {
+		return err
+	}
+	if err := checkNoVariants("a .stringsdict file", messages); err != nil {
+		return err
+	}
+	if err := checkNoExact("a .stringsdict file", messages); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	bw.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	for _, msg := range messages {
+		if !msg.hasPluralForms() {
+			continue
+		}
+		bw.WriteString("\t<key>" + xmlEscape(msg.Id) + "</key>\n\t<dict>\n")
+		bw.WriteString("\t\t<key>" + stringsdictFormatKey + "</key>\n\t\t<string>%#@value@</string>\n")
+		bw.WriteString("\t\t<key>value</key>\n\t\t<dict>\n")
+		bw.WriteString("\t\t\t<key>" + stringsdictSpecTypeKey + "</key>\n\t\t\t<string>" + stringsdictPluralType + "</string>\n")
+		bw.WriteString("\t\t\t<key>" + stringsdictValueTypeKey + "</key>\n\t\t\t<string>d</string>\n")
+		for _, category := range pluralCategories {
+			if text := msg.pluralForm(category); text != "" {
+				bw.WriteString("\t\t\t<key>" + category + "</key>\n\t\t\t<string>" + xmlEscape(templateToPrintf(text, "@", 1)) + "</string>\n")
+			}
+		}
+		bw.WriteString("\t\t</dict>\n\t</dict>\n")
+	}
+	bw.WriteString("</dict>\n</plist>\n")
+	return bw.Flush()
+}
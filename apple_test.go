@@ -0,0 +1,233 @@
+package goyai
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf16"
+)
+
+func TestParseAppleStrings(t *testing.T) {
+	testName := "TestParseAppleStrings"
+	content := "/* Greeting message */\n" +
+		"\"hello\" = \"Hello %@\";\n" +
+		"// line comment\n" +
+		"\"quote\"=\"Don't \\\"panic\\\"\\n\";\n" +
+		"/* No comment provided by engineer. */\n" +
+		"\"unicode\" = \"Xin ch\\U00e0o \\Ud83d\\Ude00\";\n" +
+		"bare_key = bare.value;\n" +
+		"\"no value\";\n"
+	expected := []appleStringsEntry{
+		{key: "hello", value: "Hello %@", comment: "Greeting message"},
+		{key: "quote", value: "Don't \"panic\"\n", comment: "line comment"},
+		{key: "unicode", value: "Xin chào 😀"},
+		{key: "bare_key", value: "bare.value"},
+	}
+	entries, err := parseAppleStrings([]byte(content))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, entries)
+	}
+
+	// UTF-16LE with byte order mark
+	utf16Content := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(content)) {
+		utf16Content = append(utf16Content, byte(u), byte(u>>8))
+	}
+	if entries, err = parseAppleStrings(utf16Content); err != nil || !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", testName, expected, entries, err)
+	}
+
+	for _, invalid := range []string{`"key" "value";`, `"key" = "value"`, `"key" = "value`} {
+		if _, err := parseAppleStrings([]byte(invalid)); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
+
+const appleStringsdictRu = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@files@ в %@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d файл</string>
+			<key>few</key>
+			<string>%d файла</string>
+			<key>many</key>
+			<string>%d файлов</string>
+			<key>other</key>
+			<string>%d файла</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestBuildI18n_Apple(t *testing.T) {
+	testName := "TestBuildI18n_Apple"
+	fsys := fstest.MapFS{
+		"Base.lproj/Localizable.strings":   {Data: []byte("\"_name\" = \"English\";\n\"hello\" = \"Hello %@\";\n\"files\" = \"%d files in %@\";\n\"k\" = \"%@ {{b}}\";\n")},
+		"ru.lproj/Localizable.strings":     {Data: []byte("/* Greeting */\n\"hello\" = \"Привет, %1$@\";\n")},
+		"ru.lproj/Localizable.stringsdict": {Data: []byte(appleStringsdictRu)},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: ".", Recursive: true, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := "Greeting", i18n.(*Goi18n).messagesStore["ru"]["hello"].Description; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		count         interface{}
+		expected      string
+	}{
+		{"en", "hello", nil, "Hello X"},
+		{"en", "k", nil, "X {{b}}"},
+		{"ru", "hello", nil, "Привет, X"},
+		{"en", "files", 5, "5 files in X"},
+		{"ru", "files", 1, "1 файл в X"},
+		{"ru", "files", 3, "3 файла в X"},
+		{"ru", "files", 5, "5 файлов в X"},
+	}
+	for _, tc := range testCases {
+		templateData := map[string]interface{}{"_0": "X"}
+		if tc.count != nil {
+			templateData = map[string]interface{}{"_0": tc.count, "_1": "X"}
+		}
+		if v := i18n.Localize(tc.locale, tc.msgId, LocalizeConfig{PluralCount: tc.count, TemplateData: templateData}); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	i18n, err = BuildI18nFromBytes([]byte(appleStringsdictRu), I18nOptions{DefaultLocale: "ru"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "5 файлов в X", i18n.Localize("ru", "files", LocalizeConfig{PluralCount: 5, TemplateData: map[string]interface{}{"_0": 5, "_1": "X"}}); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	// "{" of .strings and .stringsdict files are literal texts
+	stringsdictBraces := strings.Replace(appleStringsdictRu, "<string>%#@files@ в %@</string>", "<string>%#@files@ в {{%@}}</string>", 1)
+	i18n, err = BuildI18nFromBytes([]byte(stringsdictBraces), I18nOptions{DefaultLocale: "ru"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "5 файлов в {{X}}", i18n.Localize("ru", "files", LocalizeConfig{PluralCount: 5, TemplateData: map[string]interface{}{"_0": 5, "_1": "X"}}); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestExportApple(t *testing.T) {
+	testName := "TestExportApple"
+	yamlContentApple := `
+ru:
+  hello:
+    desc: Greeting */ message
+    other: Привет, "{{.name}}"
+  files:
+    one: "{{.n}} файл"
+    few: "{{.n}} файла"
+    many: "{{.n}} файлов"
+    other: "{{.n}} файла"
+  copied:
+    one: "{{.n}} файл скопирован в {{.dir}}"
+    other: "{{.n}} файла скопировано в {{.dir}}"
+`
+	i18n, err := BuildI18nFromBytes([]byte(yamlContentApple), I18nOptions{DefaultLocale: "ru"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportAppleStrings(buf, i18n, "ru"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	expected := "\"copied\" = \"%1$@ файла скопировано в %2$@\";\n\n\"files\" = \"%1$@ файла\";\n\n/* Greeting * / message */\n\"hello\" = \"Привет, \\\"%1$@\\\"\";\n"
+	if v := buf.String(); v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	dictBuf := new(bytes.Buffer)
+	if err := ExportAppleStringsdict(dictBuf, i18n, "ru"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if strings.Contains(dictBuf.String(), "<key>hello</key>") {
+		t.Fatalf("%s failed: unexpected content\n%s", testName, dictBuf.String())
+	}
+	// argument 1 is the plural count of format "%#@value@", placeholders start from argument 2
+	for _, expected := range []string{
+		"<key>many</key>\n\t\t\t<string>%2$@ файлов</string>",
+		"<key>one</key>\n\t\t\t<string>%2$@ файл скопирован в %3$@</string>",
+	} {
+		if !strings.Contains(dictBuf.String(), expected) {
+			t.Fatalf("%s failed: expected %#v in\n%s", testName, expected, dictBuf.String())
+		}
+	}
+	exported, err := BuildI18n(I18nOptions{FS: fstest.MapFS{
+		"ru.lproj/Localizable.strings":     {Data: buf.Bytes()},
+		"ru.lproj/Localizable.stringsdict": {Data: dictBuf.Bytes()},
+	}, ConfigFileOrDir: "ru.lproj", DefaultLocale: "ru"})
+	if exported == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for _, msgId := range []string{"hello", "files", "copied"} {
+		for _, count := range []interface{}{nil, 1, 3, 5} {
+			cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"name": "X", "n": count, "dir": "D", "_1": count, "_2": "D"}}
+			if msgId == "hello" {
+				cfg.TemplateData["_0"] = "X"
+			}
+			if e, v := i18n.Localize("ru", msgId, cfg), exported.Localize("ru", msgId, cfg); v != e {
+				t.Fatalf("%s failed for [%s/%v]: expected %#v but received %#v", testName, msgId, count, e, v)
+			}
+		}
+	}
+
+	icu, err := BuildI18nFromBytes([]byte("en:\n  _syntax: icu\n  items: \"{count, plural, one {# item} other {# items}}\"\n"), I18nOptions{DefaultLocale: "en"})
+	if icu == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAppleStrings(new(bytes.Buffer), icu, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+	if err := ExportAppleStringsdict(new(bytes.Buffer), icu, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+
+	variants, err := BuildI18nFromBytes([]byte("en:\n  hello:\n    other: Hello\n    select:\n      female: Hi madam\n"), I18nOptions{DefaultLocale: "en"})
+	if variants == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAppleStrings(new(bytes.Buffer), variants, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+	if err := ExportAppleStringsdict(new(bytes.Buffer), variants, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+
+	exact, err := BuildI18nFromBytes([]byte("en:\n  files:\n    \"=0\": No files\n    one: One file\n    other: \"{{.count}} files\"\n"), I18nOptions{DefaultLocale: "en"})
+	if exact == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportAppleStringsdict(new(bytes.Buffer), exact, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with exact plural forms", testName)
+	}
+}
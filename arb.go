@@ -0,0 +1,206 @@
+package goyai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// arbMetadata is the metadata "@<key>" of a resource of an ARB file.
+type arbMetadata struct {
+	Description string `json:"description,omitempty"`
+}
+
+// loadLangFileArb loads messages from a Flutter ARB (Application Resource Bundle) file.
+//
+// The locale of the messages is the global attribute "@@locale", or locale if the file has no "@@locale". Each resource
+// is a message in ICU MessageFormat (see Message.Syntax), so that plural and select arguments are kept as is, and the
+// "description" of the resource's metadata "@<key>" is the message's description.
+func loadLangFileArb(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	var arb map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &arb); err != nil {
+		return fmt.Errorf("error parsing ARB file: %w", err)
+	}
+	if raw, ok := arb["@@locale"]; ok {
+		if err := json.Unmarshal(raw, &locale); err != nil {
+			return fmt.Errorf("error parsing ARB file: invalid @@locale: %w", err)
+		}
+	}
+	if locale == "" {
+		return fmt.Errorf("error parsing ARB file: locale is not specified")
+	}
+	msgMap := map[string]interface{}{"_syntax": SyntaxIcu}
+	for key, raw := range arb {
+		if strings.HasPrefix(key, "@") {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return fmt.Errorf("error parsing ARB file: value of [%s] is not a string", key)
+		}
+		if key == "_display" || key == "_name" {
+			msgMap[key] = value
+			continue
+		}
+		if _, err := parseIcuMessage(value); err != nil {
+			return fmt.Errorf("error parsing ARB file at [%s]: %w", key, err)
+		}
+		msgData := map[string]string{pluralOther: value}
+		var metadata arbMetadata
+		if raw, ok := arb["@"+key]; ok && json.Unmarshal(raw, &metadata) == nil && metadata.Description != "" {
+			msgData["description"] = metadata.Description
+		}
+		msgMap[key] = msgData
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+const (
+	// arbCountArg is the name of the plural argument of messages exported by ExportArb.
+	arbCountArg = "count"
+
+	// arbSelectArg is the name of the select argument of messages with select variants exported by ExportArb.
+	arbSelectArg = "variant"
+)
+
+// ExportArb writes messages of a locale as a Flutter ARB (Application Resource Bundle) file to w.
+//
+// Messages are written as ICU MessageFormat patterns: placeholders are converted to arguments (e.g. "{name}",
+// positional placeholders "{{._0}}" to "{0}"), and plural forms to a plural argument "count" (e.g.
+// "{count, plural, one{{count} file} other{{count} files}}"). Descriptions and placeholders of messages are written
+// as metadata "@<key>". Messages in ICU MessageFormat (see Message.Syntax) are written as is. Select variants (see
+// Message.Variants) are written as a select argument "variant" (e.g. "{variant, select, female{...} other{...}}"),
+// whose value is LocalizeConfig.Select when the file is loaded back.
+//
+// Available since v0.3.0
+func ExportArb(w io.Writer, i18n I18n, locale string) error {
+	locale, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("{\n  \"@@locale\": " + jsonString(locale))
+	for _, msg := range messages {
+		placeholders, numArgs := make([]string, 0), make([]string, 0)
+		if len(msg.Variants) > 0 {
+			placeholders = append(placeholders, arbSelectArg)
+		}
+		var value string
+		value, placeholders, numArgs = arbMessage(msg, placeholders, numArgs)
+		if len(msg.Variants) > 0 {
+			keys := make([]string, 0, len(msg.Variants))
+			for key := range msg.Variants {
+				if key != pluralOther {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			var sb strings.Builder
+			sb.WriteString("{" + arbSelectArg + ", select,")
+			for _, key := range keys {
+				var text string
+				text, placeholders, numArgs = arbMessage(msg.Variants[key], placeholders, numArgs)
+				sb.WriteString(" " + key + "{" + text + "}")
+			}
+			sb.WriteString(" " + pluralOther + "{" + value + "}}")
+			value = sb.String()
+		}
+		buf.WriteString(",\n  " + jsonString(msg.Id) + ": " + jsonString(value))
+		if msg.Description == "" && len(placeholders) == 0 {
+			continue
+		}
+		buf.WriteString(",\n  " + jsonString("@"+msg.Id) + ": {")
+		if msg.Description != "" {
+			buf.WriteString("\n    \"description\": " + jsonString(msg.Description))
+			if len(placeholders) > 0 {
+				buf.WriteString(",")
+			}
+		}
+		if len(placeholders) > 0 {
+			buf.WriteString("\n    \"placeholders\": {")
+			for i, name := range placeholders {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				if containsString(numArgs, name) {
+					buf.WriteString("\n      " + jsonString(name) + ": {\"type\": \"num\"}")
+				} else {
+					buf.WriteString("\n      " + jsonString(name) + ": {}")
+				}
+			}
+			buf.WriteString("\n    }")
+		}
+		buf.WriteString("\n  }")
+	}
+	buf.WriteString("\n}\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// arbMessage returns the ICU MessageFormat pattern of a message (without its select variants) written by ExportArb,
+// appending names of its arguments to placeholders and names of its plural arguments to numArgs.
+func arbMessage(msg *Message, placeholders, numArgs []string) (string, []string, []string) {
+	if msg.Syntax == SyntaxIcu {
+		// already in ICU MessageFormat
+		nodes, _ := parseIcuMessage(msg.Other)
+		return msg.Other, icuArgNames(nodes, placeholders), arbNumArgs(nodes, numArgs)
+	}
+	value, texts := templateToIcu(msg.Other, false), []string{msg.Other}
+	if msg.hasPluralForms() {
+		if !containsString(placeholders, arbCountArg) {
+			placeholders, numArgs = append(placeholders, arbCountArg), append(numArgs, arbCountArg)
+		}
+		var sb strings.Builder
+		sb.WriteString("{" + arbCountArg + ", plural,")
+		for _, count := range msg.exactCounts() {
+			if text := msg.Exact[count]; text != "" {
+				sb.WriteString(" =" + count + "{" + templateToIcu(text, true) + "}")
+				texts = append(texts, text)
+			}
+		}
+		for _, category := range pluralCategories {
+			if text := msg.pluralForm(category); text != "" {
+				sb.WriteString(" " + category + "{" + templateToIcu(text, true) + "}")
+				texts = append(texts, text)
+			}
+		}
+		sb.WriteString("}")
+		value = sb.String()
+	}
+	for _, text := range texts {
+		for _, match := range rePlaceholderToken.FindAllStringSubmatch(text, -1) {
+			if name := icuArgName(match[1]); !containsString(placeholders, name) {
+				placeholders = append(placeholders, name)
+			}
+		}
+	}
+	return value, placeholders, numArgs
+}
+
+// arbNumArgs appends names of plural and selectordinal arguments of parsed ICU nodes, whose placeholders are of type
+// "num" in ARB metadata, to names.
+func arbNumArgs(nodes []icuNode, names []string) []string {
+	for _, node := range nodes {
+		if arg, ok := node.(*icuComplexArg); ok {
+			if arg.argType != "select" && !containsString(names, arg.name) {
+				names = append(names, arg.name)
+			}
+			for _, opt := range arg.options {
+				names = arbNumArgs(opt.nodes, names)
+			}
+		}
+	}
+	return names
+}
+
+// jsonString returns the JSON encoding of a string, without HTML escaping.
+func jsonString(s string) string {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,145 @@
+package goyai
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const arbContentEn = `{
+  "@@locale": "en",
+  "@@last_modified": "2023-01-01T00:00:00Z",
+  "hello": "Hello {name}!",
+  "@hello": {
+    "description": "Greeting message",
+    "placeholders": {"name": {"type": "String"}}
+  },
+  "files": "{count, plural, =0{No files} =1{One file} other{{count} files}} in {dir}",
+  "@files": {"placeholders": {"count": {"type": "int"}, "dir": {}}},
+  "quote": "Don't '{'panic'}'",
+  "said": "{gender, select, male{He} female{She} other{They}} said {count, plural, one{# word} other{# words}}"
+}`
+
+func TestBuildI18n_Arb(t *testing.T) {
+	testName := "TestBuildI18n_Arb"
+	fsys := fstest.MapFS{
+		"l10n/app_en.arb": {Data: []byte(arbContentEn)},
+		// no @@locale, locale is inferred from the file name
+		"l10n/app_vi.arb": {Data: []byte(`{"hello": "Xin chào {name}!", "files": "{count, plural, other{{count} tập tin}} trong {dir}"}`)},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "l10n", DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := "Greeting message", i18n.(*Goi18n).messagesStore["en"]["hello"].Description; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		count         interface{}
+		expected      string
+	}{
+		{"en", "hello", nil, "Hello Thanh!"},
+		{"vi", "hello", nil, "Xin chào Thanh!"},
+		{"en", "files", 0, "No files in Docs"},
+		{"en", "files", 1, "One file in Docs"},
+		{"en", "files", 5, "5 files in Docs"},
+		{"vi", "files", 1, "1 tập tin trong Docs"},
+		{"en", "quote", nil, "Don't {panic}"},
+		{"en", "said", 1, "He said 1 word"},
+		{"en", "said", 5, "He said 5 words"},
+	}
+	for _, tc := range testCases {
+		cfg := LocalizeConfig{PluralCount: tc.count, Select: "male", TemplateData: map[string]interface{}{"name": "Thanh", "count": tc.count, "dir": "Docs"}}
+		if v := i18n.Localize(tc.locale, tc.msgId, cfg); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	if e, v := "They said 2 words", i18n.Localize("en", "said", LocalizeConfig{TemplateData: map[string]interface{}{"count": 2}}); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	if _, err := BuildI18nFromBytes([]byte(`{"hello": "Hello {name"}`), I18nOptions{I18nFileFormat: Arb, DefaultLocale: "en"}); err == nil {
+		t.Fatalf("%s failed: expected error for invalid ICU message", testName)
+	}
+	if _, err := BuildI18nFromBytes([]byte(`{"hello": "Hello"}`), I18nOptions{I18nFileFormat: Arb}); err == nil {
+		t.Fatalf("%s failed: expected error for missing locale", testName)
+	}
+}
+
+func TestExportArb(t *testing.T) {
+	testName := "TestExportArb"
+	yamlContentArb := `
+en:
+  hello:
+    desc: Greeting message
+    other: "Hello {{.name}}!"
+  files:
+    "=0": "No files in {{.dir}}"
+    one: "One file in {{.dir}}"
+    other: "{{.count}} files in {{.dir}}"
+  quote: "Don't {panic}"
+  replied:
+    one: "{{.name}} replied to their post"
+    other: "{{.name}} and {{.count}} others replied to their post"
+    select:
+      female:
+        one: "{{.name}} replied to her post"
+        other: "{{.name}} and {{.count}} others replied to her post"
+      male: "{{.name}} replied to his post"
+`
+	testCases := []struct {
+		name, content string
+		expected      []string
+	}{
+		// messages in ICU MessageFormat are written as is
+		{"arb", arbContentEn, []string{
+			`"hello": "Hello {name}!"`,
+			`"files": "{count, plural, =0{No files} =1{One file} other{{count} files}} in {dir}"`,
+			`"quote": "Don't '{'panic'}'"`,
+			`"said": "{gender, select, male{He} female{She} other{They}} said {count, plural, one{# word} other{# words}}"`,
+		}},
+		{"yaml", yamlContentArb, []string{
+			`"hello": "Hello {name}!"`,
+			`"files": "{count, plural, =0{No files in {dir}} one{One file in {dir}} other{{count} files in {dir}}}"`,
+			`"quote": "Don''t '{'panic'}'"`,
+			// select variants are written as a select argument
+			`"replied": "{variant, select, female{{count, plural, one{{name} replied to her post} other{{name} and {count} others replied to her post}}} male{{name} replied to his post} other{{count, plural, one{{name} replied to their post} other{{name} and {count} others replied to their post}}}}"`,
+			`"variant": {}`,
+		}},
+	}
+	for _, tc := range testCases {
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{DefaultLocale: "en"})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.name, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := ExportArb(buf, i18n, "en"); err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.name, err)
+		}
+		for _, expected := range append(tc.expected, `"@@locale": "en"`, `"description": "Greeting message"`, `"count": {"type": "num"}`) {
+			if !strings.Contains(buf.String(), expected) {
+				t.Fatalf("%s failed for [%s]: expected %#v in\n%s", testName, tc.name, expected, buf.String())
+			}
+		}
+		exported, err := BuildI18nFromBytes(buf.Bytes(), I18nOptions{})
+		if exported == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s\n%s", testName, tc.name, err, buf.String())
+		}
+		for msgId := range i18n.(*Goi18n).messagesStore["en"] {
+			for _, count := range []interface{}{0, 1, 5} {
+				for _, selectKey := range []string{"", "female", "male"} {
+					cfg := LocalizeConfig{PluralCount: count, Select: selectKey, TemplateData: map[string]interface{}{"name": "X", "count": count, "dir": "D"}}
+					if e, v := i18n.Localize("en", msgId, cfg), exported.Localize("en", msgId, cfg); v != e {
+						t.Fatalf("%s failed for [%s/%s/%v/%s]: expected %#v but received %#v", testName, tc.name, msgId, count, selectKey, e, v)
+					}
+				}
+			}
+		}
+	}
+}
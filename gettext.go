@@ -0,0 +1,741 @@
+package goyai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PoContextSeparator separates the context (msgctxt) and the message id (msgid) of a gettext message in the id of the
+// loaded Message, following the gettext convention (e.g. msgctxt "menu" and msgid "open" are loaded as message id
+// "menu\x04open").
+//
+// Available since v0.3.0
+const PoContextSeparator = "\x04"
+
+// poEntry is an entry of a gettext PO/MO file.
+type poEntry struct {
+	comments    []string // extracted comments ("#. ...")
+	fuzzy       bool     // true if the entry is marked with flag "fuzzy"
+	cFormat     bool     // true if the entry is marked with flag "c-format"
+	msgctxt     string
+	hasCtxt     bool
+	msgid       string
+	msgidPlural string
+	msgstr      []string // msgstr, or msgstr[0], msgstr[1]... for plural entries
+}
+
+// id returns the id of the message built from the entry.
+func (e *poEntry) id() string {
+	if e.hasCtxt {
+		return e.msgctxt + PoContextSeparator + e.msgid
+	}
+	return e.msgid
+}
+
+var rePoKeyword = regexp.MustCompile(`^(msgctxt|msgid_plural|msgid|msgstr(?:\[(\d+)\])?)\s+(".*")$`)
+
+// parsePo parses the content of a PO file into entries.
+func parsePo(buf []byte) ([]*poEntry, error) {
+	entries := make([]*poEntry, 0)
+	entry := &poEntry{}
+	var field *string // the field continuation lines are appended to
+	hasContent := false
+	flush := func() {
+		if hasContent {
+			entries = append(entries, entry)
+		}
+		entry, field, hasContent = &poEntry{}, nil, false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf"))))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(buf)+1)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#~"):
+			// obsolete entry
+		case strings.HasPrefix(line, "#."):
+			if field != nil && len(entry.msgstr) > 0 {
+				flush()
+			}
+			entry.comments = append(entry.comments, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#,"):
+			if field != nil && len(entry.msgstr) > 0 {
+				flush()
+			}
+			for _, flag := range strings.Split(line[2:], ",") {
+				switch strings.TrimSpace(flag) {
+				case "fuzzy":
+					entry.fuzzy = true
+				case "c-format":
+					entry.cFormat = true
+				}
+			}
+		case strings.HasPrefix(line, "#"):
+			// translator comments, references, previous strings
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("error parsing PO line %d: unexpected string", lineNum)
+			}
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing PO line %d: %w", lineNum, err)
+			}
+			*field += value
+		default:
+			match := rePoKeyword.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("error parsing PO line %d: invalid syntax", lineNum)
+			}
+			value, err := strconv.Unquote(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("error parsing PO line %d: %w", lineNum, err)
+			}
+			keyword := match[1]
+			if (keyword == "msgctxt" || keyword == "msgid") && len(entry.msgstr) > 0 {
+				// a new entry starts without a blank line
+				flush()
+			}
+			hasContent = true
+			switch {
+			case keyword == "msgctxt":
+				entry.msgctxt, entry.hasCtxt = value, true
+				field = &entry.msgctxt
+			case keyword == "msgid":
+				entry.msgid = value
+				field = &entry.msgid
+			case keyword == "msgid_plural":
+				entry.msgidPlural = value
+				field = &entry.msgidPlural
+			default:
+				index := 0
+				if match[2] != "" {
+					index, _ = strconv.Atoi(match[2])
+				}
+				for len(entry.msgstr) <= index {
+					entry.msgstr = append(entry.msgstr, "")
+				}
+				entry.msgstr[index] = value
+				field = &entry.msgstr[index]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
+
+// moMagic is the magic number of MO files.
+const moMagic = 0x950412de
+
+// parseMo parses the content of a MO file into entries.
+func parseMo(buf []byte) ([]*poEntry, error) {
+	if len(buf) < 20 {
+		return nil, fmt.Errorf("invalid MO file: file too short")
+	}
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(buf) == moMagic:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(buf) == moMagic:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid MO file: invalid magic number")
+	}
+	numStrings := order.Uint32(buf[8:])
+	origTable, transTable := order.Uint32(buf[12:]), order.Uint32(buf[16:])
+	readString := func(table, index uint32) (string, error) {
+		pos := uint64(table) + uint64(index)*8
+		if pos+8 > uint64(len(buf)) {
+			return "", fmt.Errorf("invalid MO file: string #%d out of range", index)
+		}
+		length, offset := uint64(order.Uint32(buf[pos:])), uint64(order.Uint32(buf[pos+4:]))
+		if offset+length > uint64(len(buf)) {
+			return "", fmt.Errorf("invalid MO file: string #%d out of range", index)
+		}
+		return string(buf[offset : offset+length]), nil
+	}
+	entries := make([]*poEntry, 0, numStrings)
+	for i := uint32(0); i < numStrings; i++ {
+		orig, err := readString(origTable, i)
+		if err != nil {
+			return nil, err
+		}
+		trans, err := readString(transTable, i)
+		if err != nil {
+			return nil, err
+		}
+		entry := &poEntry{msgstr: strings.Split(trans, "\x00")}
+		if pos := strings.Index(orig, PoContextSeparator); pos >= 0 {
+			entry.msgctxt, entry.hasCtxt, orig = orig[:pos], true, orig[pos+1:]
+		}
+		ids := strings.SplitN(orig, "\x00", 2)
+		entry.msgid = ids[0]
+		if len(ids) > 1 {
+			entry.msgidPlural = ids[1]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func loadLangFilePo(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	entries, err := parsePo(buf)
+	if err != nil {
+		return err
+	}
+	return loadGettextEntries(localesStore, messagesStore, entries, locale)
+}
+
+func loadLangFileMo(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	entries, err := parseMo(buf)
+	if err != nil {
+		return err
+	}
+	return loadGettextEntries(localesStore, messagesStore, entries, locale)
+}
+
+// loadGettextEntries loads messages from gettext entries.
+//
+// If locale is empty, the locale is taken from the header "Language" of the file. Fuzzy entries and entries without
+// translation are ignored. Translations of entries with flag "c-format" are converted to message templates (see
+// function convertPrintfFormat); MO files do not keep flags, so their translations are loaded as message templates.
+func loadGettextEntries(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, entries []*poEntry, locale string) error {
+	headers := make(map[string]string)
+	for _, entry := range entries {
+		if entry.msgid == "" && !entry.hasCtxt && len(entry.msgstr) > 0 {
+			for _, line := range strings.Split(entry.msgstr[0], "\n") {
+				if kv := strings.SplitN(line, ":", 2); len(kv) == 2 {
+					headers[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.TrimSpace(kv[1])
+				}
+			}
+		}
+	}
+	if locale == "" {
+		if locale = headers["language"]; locale == "" {
+			return fmt.Errorf("error parsing gettext file: missing header 'Language'")
+		}
+	}
+	categories, err := gettextPluralCategories(locale, headers["plural-forms"])
+	if err != nil {
+		return err
+	}
+
+	msgMap := make(map[string]interface{})
+	for _, entry := range entries {
+		if entry.msgid == "" || entry.fuzzy || len(entry.msgstr) == 0 {
+			continue
+		}
+		msgstr := entry.msgstr
+		if entry.cFormat {
+			msgstr = make([]string, len(entry.msgstr))
+			for i, text := range entry.msgstr {
+				msgstr[i] = convertPrintfFormat(escapeTemplateBraces(text), reCFormatSpec)
+			}
+		}
+		msgData := make(map[string]string)
+		if entry.msgidPlural == "" {
+			msgData[pluralOther] = msgstr[0]
+		} else {
+			for category, index := range categories {
+				if index < len(msgstr) && msgstr[index] != "" {
+					msgData[category] = msgstr[index]
+				}
+			}
+		}
+		if msgData[pluralOther] == "" {
+			// translations of all forms are empty, or the form "other" is missing
+			continue
+		}
+		if len(entry.comments) > 0 {
+			msgData["description"] = strings.Join(entry.comments, "\n")
+		}
+		msgMap[entry.id()] = msgData
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+var rePluralForms = regexp.MustCompile(`nplurals\s*=\s*(\d+)\s*;\s*plural\s*=\s*([^;]+)`)
+
+// gettextPluralCategories maps CLDR plural categories of a locale to indexes of msgstr[n], by evaluating the plural
+// expression of the header "Plural-Forms" (e.g. "nplurals=2; plural=(n != 1);") with sample numbers of each category.
+//
+// If pluralForms is empty, "nplurals=2; plural=(n != 1);" is assumed.
+func gettextPluralCategories(locale, pluralForms string) (map[string]int, error) {
+	if strings.TrimSpace(pluralForms) == "" {
+		pluralForms = "nplurals=2; plural=(n != 1);"
+	}
+	match := rePluralForms.FindStringSubmatch(pluralForms)
+	if match == nil {
+		return nil, fmt.Errorf("error parsing gettext header 'Plural-Forms: %s'", pluralForms)
+	}
+	nplurals, _ := strconv.Atoi(match[1])
+	expr, err := parsePluralExpr(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing gettext header 'Plural-Forms: %s': %w", pluralForms, err)
+	}
+	rules := lookupPluralRules(cardinalRulesTable, locale)
+	categories := make(map[string]int)
+	samples := make([]int64, 0, 1024)
+	for n := int64(0); n <= 1000; n++ {
+		samples = append(samples, n)
+	}
+	for n := int64(10000); n <= 1e18; n *= 10 {
+		// large numbers, e.g. the form "many" of French is for millions
+		samples = append(samples, n)
+	}
+	for _, n := range samples {
+		operands, _ := parsePluralOperands(strconv.FormatInt(n, 10))
+		category := rules.category(operands)
+		if _, ok := categories[category]; !ok {
+			if index := expr(n); index >= 0 && index < int64(nplurals) {
+				categories[category] = int(index)
+			}
+		}
+	}
+	if _, ok := categories[pluralOther]; !ok && nplurals > 0 {
+		// the form "other" of some locales is only for decimal numbers, use the last form
+		categories[pluralOther] = nplurals - 1
+	}
+	return categories, nil
+}
+
+// pluralExpr is a compiled C expression of the header "Plural-Forms", returning index of msgstr[n] for n.
+type pluralExpr func(n int64) int64
+
+// pluralExprParser parses C expressions of the header "Plural-Forms" (e.g. "n%10==1 && n%100!=11 ? 0 : 1").
+type pluralExprParser struct {
+	tokens []string
+	pos    int
+}
+
+var rePluralExprToken = regexp.MustCompile(`\s*(\d+|n|&&|\|\||==|!=|<=|>=|[-+*/%<>!?:()])`)
+
+func parsePluralExpr(input string) (pluralExpr, error) {
+	p := &pluralExprParser{}
+	for rest := strings.TrimSpace(input); rest != ""; rest = strings.TrimSpace(rest) {
+		loc := rePluralExprToken.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] != 0 {
+			return nil, fmt.Errorf("invalid plural expression '%s'", input)
+		}
+		p.tokens = append(p.tokens, rest[loc[2]:loc[3]])
+		rest = rest[loc[1]:]
+	}
+	expr, err := p.parseTernary()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected token '%s'", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid plural expression '%s': %w", input, err)
+	}
+	return expr, nil
+}
+
+func (p *pluralExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *pluralExprParser) parseTernary() (pluralExpr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil || p.peek() != "?" {
+		return cond, err
+	}
+	p.pos++
+	ifTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ":" {
+		return nil, fmt.Errorf("expected ':'")
+	}
+	p.pos++
+	ifFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int64) int64 {
+		if cond(n) != 0 {
+			return ifTrue(n)
+		}
+		return ifFalse(n)
+	}, nil
+}
+
+// pluralExprOperators lists binary operators by precedence, lowest first.
+var pluralExprOperators = [][]string{{"||"}, {"&&"}, {"==", "!="}, {"<", "<=", ">", ">="}, {"+", "-"}, {"*", "/", "%"}}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *pluralExprParser) parseBinary(level int) (pluralExpr, error) {
+	if level >= len(pluralExprOperators) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		found := false
+		for _, candidate := range pluralExprOperators[level] {
+			found = found || op == candidate
+		}
+		if !found {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		switch op {
+		case "||":
+			left = func(n int64) int64 { return boolToInt64(l(n) != 0 || r(n) != 0) }
+		case "&&":
+			left = func(n int64) int64 { return boolToInt64(l(n) != 0 && r(n) != 0) }
+		case "==":
+			left = func(n int64) int64 { return boolToInt64(l(n) == r(n)) }
+		case "!=":
+			left = func(n int64) int64 { return boolToInt64(l(n) != r(n)) }
+		case "<":
+			left = func(n int64) int64 { return boolToInt64(l(n) < r(n)) }
+		case "<=":
+			left = func(n int64) int64 { return boolToInt64(l(n) <= r(n)) }
+		case ">":
+			left = func(n int64) int64 { return boolToInt64(l(n) > r(n)) }
+		case ">=":
+			left = func(n int64) int64 { return boolToInt64(l(n) >= r(n)) }
+		case "+":
+			left = func(n int64) int64 { return l(n) + r(n) }
+		case "-":
+			left = func(n int64) int64 { return l(n) - r(n) }
+		case "*":
+			left = func(n int64) int64 { return l(n) * r(n) }
+		case "/", "%":
+			isDiv := op == "/"
+			left = func(n int64) int64 {
+				divisor := r(n)
+				if divisor == 0 {
+					return 0
+				}
+				if isDiv {
+					return l(n) / divisor
+				}
+				return l(n) % divisor
+			}
+		}
+	}
+}
+
+func (p *pluralExprParser) parseUnary() (pluralExpr, error) {
+	switch token := p.peek(); token {
+	case "!", "-":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if token == "!" {
+			return func(n int64) int64 { return boolToInt64(operand(n) == 0) }, nil
+		}
+		return func(n int64) int64 { return -operand(n) }, nil
+	case "(":
+		p.pos++
+		expr, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		p.pos++
+		return expr, nil
+	case "n":
+		p.pos++
+		return func(n int64) int64 { return n }, nil
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		value, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected token '%s'", token)
+		}
+		p.pos++
+		return func(int64) int64 { return value }, nil
+	}
+}
+
+// gettextPluralForms builds the header "Plural-Forms" of a locale from its CLDR cardinal plural rules, and returns the
+// plural categories mapped to msgstr[0], msgstr[1]..., in order.
+func gettextPluralForms(locale string) (string, []string) {
+	rules := lookupPluralRules(cardinalRulesTable, locale)
+	categories := make([]string, 0, 6)
+	conditions := make([]string, 0, 6)
+	if rules != nil {
+		for _, rule := range rules.rules {
+			if rule.category == pluralOther {
+				continue
+			}
+			if cond := gettextCondition(rule.condition); cond != "0" {
+				categories = append(categories, rule.category)
+				conditions = append(conditions, cond)
+			}
+		}
+	}
+	categories = append(categories, pluralOther)
+	expr := strconv.Itoa(len(conditions))
+	for i := len(conditions) - 1; i >= 0; i-- {
+		expr = fmt.Sprintf("%s ? %d : %s", conditions[i], i, expr)
+	}
+	return fmt.Sprintf("nplurals=%d; plural=%s;", len(categories), expr), categories
+}
+
+// gettextCondition converts the condition of a CLDR plural rule to a C expression for integers: operands n and i are
+// the integer, other operands are zero. It returns "0" if the condition never matches integers.
+func gettextCondition(condition [][]pluralRelation) string {
+	ors := make([]string, 0, len(condition))
+	for _, and := range condition {
+		ands := make([]string, 0, len(and))
+		matchable := true
+		for _, relation := range and {
+			if relation.operand == 'n' || relation.operand == 'i' {
+				ands = append(ands, gettextRelation(relation))
+				continue
+			}
+			if !relation.match(&pluralOperands{}) {
+				matchable = false
+				break
+			}
+		}
+		if !matchable {
+			continue
+		}
+		if len(ands) == 0 {
+			return "1"
+		}
+		if len(ands) > 1 && len(condition) > 1 {
+			ors = append(ors, "("+strings.Join(ands, " && ")+")")
+		} else {
+			ors = append(ors, strings.Join(ands, " && "))
+		}
+	}
+	if len(ors) == 0 {
+		return "0"
+	}
+	return "(" + strings.Join(ors, " || ") + ")"
+}
+
+func gettextRelation(relation pluralRelation) string {
+	operand := "n"
+	if relation.mod > 0 {
+		operand = fmt.Sprintf("n%%%d", relation.mod)
+	}
+	ranges := make([]string, 0, len(relation.ranges))
+	for _, rng := range relation.ranges {
+		if rng[0] == rng[1] {
+			ranges = append(ranges, fmt.Sprintf("%s==%d", operand, rng[0]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%s>=%d && %s<=%d", operand, rng[0], operand, rng[1]))
+		}
+	}
+	if len(ranges) == 1 && !strings.Contains(ranges[0], "&&") {
+		if relation.negate {
+			return strings.Replace(ranges[0], "==", "!=", 1)
+		}
+		return ranges[0]
+	}
+	if len(ranges) == 1 && !relation.negate {
+		return ranges[0]
+	}
+	result := "(" + strings.Join(ranges, " || ") + ")"
+	if relation.negate {
+		return "!" + result
+	}
+	return result
+}
+
+// ExportPo writes messages of a locale (without messages from fallback/parent locales) as a gettext PO file to w.
+// The header "Plural-Forms" is built from the CLDR plural rules of the locale.
+//
+// Messages with plural forms are written as plural entries (msgid_plural, msgstr[n]), descriptions are written as
+// extracted comments ("#. ..."). Message ids containing PoContextSeparator are written with msgctxt.
+//
+// PO files have no counterpart of select variants (see Message.Variants), exact plural forms (see Message.Exact) and
+// messages in ICU MessageFormat (see Message.Syntax), an error is returned if a message has them.
+//
+// Available since v0.3.0
+func ExportPo(w io.Writer, i18n I18n, locale string) error {
+	locale, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	return writePo(w, locale, messages, false)
+}
+
+// ExportPot writes messages of the default locale as a gettext PO template (POT) file, whose translations are
+// empty, to w. The POT file can be used by translators to start new translations (e.g. via Poedit).
+//
+// Available since v0.3.0
+func ExportPot(w io.Writer, i18n I18n) error {
+	locale, messages, err := exportMessages(i18n, "")
+	if err != nil {
+		return err
+	}
+	return writePo(w, locale, messages, true)
+}
+
+func writePo(w io.Writer, locale string, messages []*Message, template bool) error {
+	if !template {
+		if err := checkNoIcuMessages("a PO file", messages); err != nil {
+			return err
+		}
+		if err := checkNoVariants("a PO file", messages); err != nil {
+			return err
+		}
+		if err := checkNoExact("a PO file", messages); err != nil {
+			return err
+		}
+	}
+	pluralForms, categories := gettextPluralForms(locale)
+	bw := bufio.NewWriter(w)
+	bw.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	if !template {
+		bw.WriteString(`"Language: ` + locale + `\n"` + "\n")
+	}
+	bw.WriteString(`"MIME-Version: 1.0\n"` + "\n")
+	bw.WriteString(`"Content-Type: text/plain; charset=UTF-8\n"` + "\n")
+	bw.WriteString(`"Content-Transfer-Encoding: 8bit\n"` + "\n")
+	if !template {
+		bw.WriteString(`"Plural-Forms: ` + pluralForms + `\n"` + "\n")
+	}
+	for _, msg := range messages {
+		bw.WriteString("\n")
+		if msg.Description != "" {
+			for _, line := range strings.Split(msg.Description, "\n") {
+				bw.WriteString("#. " + line + "\n")
+			}
+		}
+		msgid := msg.Id
+		if pos := strings.Index(msgid, PoContextSeparator); pos >= 0 {
+			writePoString(bw, "msgctxt", msgid[:pos])
+			msgid = msgid[pos+len(PoContextSeparator):]
+		}
+		writePoString(bw, "msgid", msgid)
+		if !msg.hasPluralForms() {
+			writePoString(bw, "msgstr", exportValue(msg.Other, template))
+			continue
+		}
+		writePoString(bw, "msgid_plural", msgid)
+		if template {
+			writePoString(bw, "msgstr[0]", "")
+			writePoString(bw, "msgstr[1]", "")
+			continue
+		}
+		for i, category := range categories {
+			value := msg.pluralForm(category)
+			if value == "" {
+				value = msg.Other
+			}
+			writePoString(bw, fmt.Sprintf("msgstr[%d]", i), value)
+		}
+	}
+	return bw.Flush()
+}
+
+func exportValue(value string, template bool) string {
+	if template {
+		return ""
+	}
+	return value
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// writePoString writes a keyword and its quoted value, multi-line values are split into one string per line.
+func writePoString(w *bufio.Writer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 1 {
+		w.WriteString(keyword + ` "` + poEscaper.Replace(value) + "\"\n")
+		return
+	}
+	w.WriteString(keyword + " \"\"\n")
+	for _, line := range lines {
+		w.WriteString(`"` + poEscaper.Replace(line) + "\"\n")
+	}
+}
+
+// exportMessages returns messages of a locale (the default locale if empty), sorted by id, used by exporters.
+func exportMessages(i18n I18n, locale string) (string, []*Message, error) {
+	impl, ok := i18n.(*Goi18n)
+	if !ok {
+		return "", nil, ErrExportNotSupported
+	}
+	if locale == "" {
+		locale = impl.defaultLocale
+	}
+	locale = canonicalLocale(locale)
+	if impl.locales[locale] == nil {
+		return "", nil, fmt.Errorf("locale [%s] not exist", locale)
+	}
+	messages := make([]*Message, 0, len(impl.messagesStore[locale]))
+	for _, msg := range impl.messagesStore[locale] {
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Id < messages[j].Id })
+	return locale, messages, nil
+}
+
+// checkNoIcuMessages returns an error if a message is in ICU MessageFormat (see Message.Syntax), which cannot be
+// written to files of an export format (e.g. "a PO file").
+func checkNoIcuMessages(format string, messages []*Message) error {
+	for _, msg := range messages {
+		if msg.Syntax == SyntaxIcu {
+			return fmt.Errorf("message [%s] is in ICU MessageFormat, which cannot be written to %s", msg.Id, format)
+		}
+	}
+	return nil
+}
+
+// checkNoVariants returns an error if a message has select variants (see Message.Variants), which cannot be written
+// to files of an export format (e.g. "a PO file").
+func checkNoVariants(format string, messages []*Message) error {
+	for _, msg := range messages {
+		if len(msg.Variants) > 0 {
+			return fmt.Errorf("message [%s] has select variants, which cannot be written to %s", msg.Id, format)
+		}
+	}
+	return nil
+}
+
+// checkNoExact returns an error if a message has exact plural forms (see Message.Exact), which cannot be written to
+// files of an export format (e.g. "a PO file").
+func checkNoExact(format string, messages []*Message) error {
+	for _, msg := range messages {
+		if len(msg.Exact) > 0 {
+			return fmt.Errorf("message [%s] has exact plural forms, which cannot be written to %s", msg.Id, format)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package goyai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const poContentRu = `# Russian translations
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Greeting message
+#: main.go:10
+msgid "hello"
+msgstr "Привет, {{.name}}"
+
+msgctxt "menu"
+msgid "open"
+msgstr "Открыть"
+msgid "multiline"
+msgstr ""
+"Строка 1\n"
+"Строка 2"
+
+#, fuzzy
+msgid "fuzzy"
+msgstr "Нечёткий"
+
+msgid "untranslated"
+msgstr ""
+
+msgid "files"
+msgid_plural "files"
+msgstr[0] "{{.n}} файл"
+msgstr[1] "{{.n}} файла"
+msgstr[2] "{{.n}} файлов"
+
+#, c-format
+msgid "downloaded"
+msgid_plural "downloaded"
+msgstr[0] "%s: загружен %d файл {{x}}"
+msgstr[1] "%s: загружено %d файла {{x}}"
+msgstr[2] "%2$s: загружено %1$d файлов, 100%%"
+
+#~ msgid "obsolete"
+#~ msgstr "Устаревший"
+`
+
+func TestParsePluralExpr(t *testing.T) {
+	testName := "TestParsePluralExpr"
+	testCases := []struct {
+		expr     string
+		expected map[int64]int64
+	}{
+		{"0", map[int64]int64{0: 0, 1: 0, 5: 0}},
+		{"(n != 1)", map[int64]int64{0: 1, 1: 0, 2: 1}},
+		{"n>1", map[int64]int64{0: 0, 1: 0, 2: 1}},
+		{"n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2",
+			map[int64]int64{1: 0, 2: 1, 5: 2, 11: 2, 12: 2, 21: 0, 22: 1, 111: 2}},
+		{"n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5",
+			map[int64]int64{0: 0, 1: 1, 2: 2, 3: 3, 11: 4, 100: 5, 102: 5}},
+		{"!(n - 1) + n * 2 / 2 % 3", map[int64]int64{1: 2, 2: 2, 3: 0}},
+	}
+	for _, tc := range testCases {
+		expr, err := parsePluralExpr(tc.expr)
+		if err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.expr, err)
+		}
+		for n, e := range tc.expected {
+			if v := expr(n); v != e {
+				t.Fatalf("%s failed for [%s] with n=%d: expected %d but received %d", testName, tc.expr, n, e, v)
+			}
+		}
+	}
+	for _, input := range []string{"", "n ==", "(n", "n ? 1", "x", "n 1"} {
+		if _, err := parsePluralExpr(input); err == nil {
+			t.Fatalf("%s failed: expected error for [%s]", testName, input)
+		}
+	}
+}
+
+func TestGettextPluralForms(t *testing.T) {
+	testName := "TestGettextPluralForms"
+	testCases := []struct {
+		locale      string
+		pluralForms string
+		categories  []string
+	}{
+		{"en", "nplurals=2; plural=(n==1) ? 0 : 1;", []string{"one", "other"}},
+		{"ja", "nplurals=1; plural=0;", []string{"other"}},
+		{"fr", "", []string{"one", "many", "other"}},
+		{"ar", "", []string{"zero", "one", "two", "few", "many", "other"}},
+	}
+	for _, tc := range testCases {
+		pluralForms, categories := gettextPluralForms(tc.locale)
+		if tc.pluralForms != "" && pluralForms != tc.pluralForms {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.locale, tc.pluralForms, pluralForms)
+		}
+		if strings.Join(categories, ",") != strings.Join(tc.categories, ",") {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.locale, tc.categories, categories)
+		}
+		// the generated header must map categories back to the same indexes
+		mapping, err := gettextPluralCategories(tc.locale, pluralForms)
+		if err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.locale, err)
+		}
+		for i, category := range categories {
+			if mapping[category] != i {
+				t.Fatalf("%s failed for [%s/%s]: expected %d but received %d", testName, tc.locale, category, i, mapping[category])
+			}
+		}
+	}
+}
+
+func TestBuildI18n_Po(t *testing.T) {
+	testName := "TestBuildI18n_Po"
+	i18n, err := BuildI18nFromBytes([]byte(poContentRu), I18nOptions{DefaultLocale: "ru"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	testCases := []struct {
+		msgId    string
+		cfg      *LocalizeConfig
+		expected string
+	}{
+		{"hello", &LocalizeConfig{TemplateData: map[string]interface{}{"name": "Иван"}}, "Привет, Иван"},
+		{"menu" + PoContextSeparator + "open", nil, "Открыть"},
+		{"open", nil, ""},
+		{"multiline", nil, "Строка 1\nСтрока 2"},
+		{"fuzzy", nil, ""},
+		{"untranslated", nil, ""},
+		{"obsolete", nil, ""},
+		{"files", &LocalizeConfig{PluralCount: 1, TemplateData: map[string]interface{}{"n": 1}}, "1 файл"},
+		{"files", &LocalizeConfig{PluralCount: 3, TemplateData: map[string]interface{}{"n": 3}}, "3 файла"},
+		{"files", &LocalizeConfig{PluralCount: 11, TemplateData: map[string]interface{}{"n": 11}}, "11 файлов"},
+		{"files", &LocalizeConfig{PluralCount: "1.5", TemplateData: map[string]interface{}{"n": "1.5"}}, "1.5 файлов"},
+		{"downloaded", &LocalizeConfig{PluralCount: 1, TemplateData: map[string]interface{}{"_0": "X", "_1": 1}}, "X: загружен 1 файл {{x}}"},
+		{"downloaded", &LocalizeConfig{PluralCount: 5, TemplateData: map[string]interface{}{"_0": 5, "_1": "X"}}, "X: загружено 5 файлов, 100%"},
+	}
+	for _, tc := range testCases {
+		var params []interface{}
+		if tc.cfg != nil {
+			params = append(params, tc.cfg)
+		}
+		if v := i18n.Localize("ru", tc.msgId, params...); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.msgId, tc.expected, v)
+		}
+	}
+	goi18n := i18n.(*Goi18n)
+	if e, v := "Greeting message", goi18n.messagesStore["ru"]["hello"].Description; v != e {
+		t.Fatalf("%s failed: expected description %#v but received %#v", testName, e, v)
+	}
+
+	if _, err := BuildI18nFromBytes([]byte("msgid \"hello\"\nmsgstr \"Hello\"\n"), I18nOptions{I18nFileFormat: Po}); err == nil {
+		t.Fatalf("%s failed: expected error for missing header 'Language'", testName)
+	}
+	if _, err := BuildI18nFromBytes([]byte("msgid \"hello\"\nmsgstr Hello\n"), I18nOptions{I18nFileFormat: Po}); err == nil {
+		t.Fatalf("%s failed: expected error for invalid syntax", testName)
+	}
+
+	fsys := fstest.MapFS{
+		"locales/ru.po":        {Data: []byte(poContentRu)},
+		"locales/vi/common.po": {Data: []byte("msgid \"hello\"\nmsgstr \"Xin chào {{.name}}\"\n")},
+	}
+	i18n, err = BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales", Recursive: true, LocaleFromFileName: true})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Xin chào Thanh", i18n.Localize("vi", "hello", "Thanh"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+// _buildMo builds a little-endian MO file from original/translation pairs.
+func _buildMo(pairs [][2]string) []byte {
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+	n := uint32(len(pairs))
+	origTable, transTable := uint32(28), 28+n*8
+	offset := transTable + n*8
+	header := []uint32{moMagic, 0, n, origTable, transTable, 0, 0}
+	tables := make([]uint32, 0, n*4)
+	data := new(bytes.Buffer)
+	for col := 0; col < 2; col++ {
+		for _, pair := range pairs {
+			tables = append(tables, uint32(len(pair[col])), offset+uint32(data.Len()))
+			data.WriteString(pair[col] + "\x00")
+		}
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, header)
+	binary.Write(buf, binary.LittleEndian, tables)
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+func TestBuildI18n_Mo(t *testing.T) {
+	testName := "TestBuildI18n_Mo"
+	mo := _buildMo([][2]string{
+		{"", "Language: vi\nPlural-Forms: nplurals=1; plural=0;\n"},
+		{"hello", "Xin chào"},
+		{"menu\x04open", "Mở"},
+		{"files\x00files", "{{.n}} tập tin"},
+	})
+	i18n, err := BuildI18nFromBytes(mo, I18nOptions{DefaultLocale: "vi"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	testCases := []struct {
+		msgId    string
+		params   []interface{}
+		expected string
+	}{
+		{"hello", nil, "Xin chào"},
+		{"menu" + PoContextSeparator + "open", nil, "Mở"},
+		{"files", []interface{}{LocalizeConfig{PluralCount: 2, TemplateData: map[string]interface{}{"n": 2}}}, "2 tập tin"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize("vi", tc.msgId, tc.params...); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.msgId, tc.expected, v)
+		}
+	}
+	if _, err := BuildI18nFromBytes(mo[:30], I18nOptions{I18nFileFormat: Mo}); err == nil {
+		t.Fatalf("%s failed: expected error for truncated MO file", testName)
+	}
+}
+
+func TestExportPo(t *testing.T) {
+	testName := "TestExportPo"
+	yamlContentPo := `
+en:
+  hello:
+    desc: "Greeting\nmessage"
+    other: "Hello \"{{.name}}\""
+  "menu\x04open": Open
+  files:
+    one: "{{.n}} file"
+    other: "{{.n}} files"
+ru:
+  hello: "Привет, {{.name}}"
+  files:
+    one: "{{.n}} файл"
+    few: "{{.n}} файла"
+    many: "{{.n}} файлов"
+    other: "{{.n}} файла (дробь)"
+`
+	i18n, err := BuildI18nFromBytes([]byte(yamlContentPo), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for _, locale := range []string{"en", "ru"} {
+		buf := new(bytes.Buffer)
+		if err := ExportPo(buf, i18n, locale); err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, locale, err)
+		}
+		exported, err := BuildI18nFromBytes(buf.Bytes(), I18nOptions{I18nFileFormat: Po})
+		if exported == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s\n%s", testName, locale, err, buf.String())
+		}
+		for msgId := range i18n.(*Goi18n).messagesStore[locale] {
+			for _, count := range []interface{}{nil, 1, 2, 5, 21, "1.5"} {
+				cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"name": "X", "n": count}}
+				if e, v := i18n.Localize(locale, msgId, cfg), exported.Localize(locale, msgId, cfg); v != e {
+					t.Fatalf("%s failed for [%s/%s/%v]: expected %#v but received %#v", testName, locale, msgId, count, e, v)
+				}
+			}
+		}
+		if locale == "en" && exported.(*Goi18n).messagesStore["en"]["hello"].Description != "Greeting\nmessage" {
+			t.Fatalf("%s failed: description is not exported\n%s", testName, buf.String())
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportPot(buf, i18n); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	pot := buf.String()
+	for _, expected := range []string{"#. Greeting\n#. message\nmsgid \"hello\"\nmsgstr \"\"\n", "msgctxt \"menu\"\nmsgid \"open\"\n", "msgid_plural \"files\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n"} {
+		if !strings.Contains(pot, expected) {
+			t.Fatalf("%s failed: expected POT to contain %#v\n%s", testName, expected, pot)
+		}
+	}
+	if strings.Contains(pot, "Language:") {
+		t.Fatalf("%s failed: POT must not have header 'Language'\n%s", testName, pot)
+	}
+
+	variants, err := BuildI18nFromBytes([]byte("en:\n  hello:\n    other: Hello\n    select:\n      female: Hi madam\n"), I18nOptions{DefaultLocale: "en"})
+	if variants == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportPo(buf, variants, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+	if err := ExportPot(buf, variants); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	icu, err := BuildI18nFromBytes([]byte("en:\n  _syntax: icu\n  items: \"{count, plural, one {# item} other {# items}}\"\n"), I18nOptions{DefaultLocale: "en"})
+	if icu == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportPo(buf, icu, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+
+	if err := ExportPo(buf, i18n, "fr"); err == nil {
+		t.Fatalf("%s failed: expected error for non-exist locale", testName)
+	}
+	if err := ExportPo(buf, &wrappedI18n{I18n: i18n}, "en"); err != ErrExportNotSupported {
+		t.Fatalf("%s failed: expected %s but received %s", testName, ErrExportNotSupported, err)
+	}
+
+	exact, err := BuildI18nFromBytes([]byte("en:\n  files:\n    \"=0\": No files\n    one: One file\n    other: \"{{.count}} files\"\n"), I18nOptions{DefaultLocale: "en"})
+	if exact == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportPo(new(bytes.Buffer), exact, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with exact plural forms", testName)
+	}
+}
@@ -1,8 +1,9 @@
 module github.com/btnguyen2k/goyai
 
-go 1.13
+go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/btnguyen2k/consu/reddo v0.1.9
 	gopkg.in/yaml.v3 v3.0.1
 )
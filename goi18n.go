@@ -0,0 +1,120 @@
+package goyai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// goI18nReservedKeys are the (lower-cased) keys of a message in go-i18n v2 message files.
+var goI18nReservedKeys = []string{"id", "description", "hash", "leftdelim", "rightdelim", "zero", "one", "two", "few", "many", "other"}
+
+// isGoI18nMessage returns true if data is a message of a go-i18n message file: a string, or a map having any of the
+// reserved keys with a string value. Other maps contain nested messages.
+func isGoI18nMessage(data interface{}) bool {
+	switch v := data.(type) {
+	case string:
+		return true
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && containsString(goI18nReservedKeys, strings.ToLower(key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectGoI18nMessages collects messages of go-i18n message file data into msgMap. Ids of nested messages are joined
+// with "." (e.g. {"nav": {"home": "Home"}} → "nav.home"), and items of an array are messages with key "id".
+func collectGoI18nMessages(prefix string, data interface{}, msgMap map[string]interface{}) error {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := putGoI18nMessage("", item, msgMap); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		for key, value := range v {
+			var err error
+			if isGoI18nMessage(value) {
+				err = putGoI18nMessage(prefix+key, value, msgMap)
+			} else {
+				err = collectGoI18nMessages(prefix+key+".", value, msgMap)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("error parsing go-i18n message file: unexpected data of type %T at [%s]", data, strings.TrimSuffix(prefix, "."))
+}
+
+// putGoI18nMessage puts a message into msgMap. The key "id" of the message overrides msgId, the key "hash" is ignored.
+func putGoI18nMessage(msgId string, data interface{}, msgMap map[string]interface{}) error {
+	msgData, ok := data.(map[string]interface{})
+	if !ok {
+		if msgId == "" {
+			return fmt.Errorf("error parsing go-i18n message file: message without id")
+		}
+		msgMap[msgId] = data
+		return nil
+	}
+	converted := make(map[string]interface{}, len(msgData))
+	for key, value := range msgData {
+		switch strings.ToLower(key) {
+		case "id":
+			msgId, _ = value.(string)
+		case "hash":
+		default:
+			converted[key] = value
+		}
+	}
+	if msgId == "" {
+		return fmt.Errorf("error parsing go-i18n message file: message without id")
+	}
+	msgMap[msgId] = converted
+	return nil
+}
+
+// loadLangFileGoI18n loads messages of a locale from a go-i18n v2 message file (e.g. "active.vi.toml") in JSON, YAML
+// or TOML format.
+func loadLangFileGoI18n(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, format I18nFileFormat, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing go-i18n message file: locale is not specified")
+	}
+	var data interface{}
+	var err error
+	switch format {
+	case Json:
+		err = json.Unmarshal(buf, &data)
+	case Yaml:
+		if err = yaml.NewDecoder(bytes.NewReader(buf)).Decode(&data); err == io.EOF {
+			err = nil
+		}
+	case Toml:
+		var tomlData map[string]interface{}
+		err = toml.Unmarshal(buf, &tomlData)
+		data = tomlData
+	default:
+		return ErrInvalidFileFormat
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing go-i18n message file: %w", err)
+	}
+	msgMap := make(map[string]interface{})
+	if data != nil {
+		if err := collectGoI18nMessages("", data, msgMap); err != nil {
+			return err
+		}
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
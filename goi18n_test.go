@@ -0,0 +1,91 @@
+package goyai
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuildI18n_GoI18nCompat(t *testing.T) {
+	testName := "TestBuildI18n_GoI18nCompat"
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+HelloWorld = "Hello World!"
+
+[PersonCats]
+description = "The number of cats a person has"
+hash = "sha1-0123456789abcdef"
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+
+[Custom]
+leftDelim = "<<"
+rightDelim = ">>"
+other = "Hi <<.Name>>, {{not a placeholder}}"
+
+[nav]
+home = "Home"
+`)},
+		"active.vi.json": {Data: []byte(`{
+  "HelloWorld": "Chào thế giới!",
+  "PersonCats": {"id": "PersonCats", "Other": "{{.Name}} có {{.Count}} con mèo."},
+  "nav": {"home": {"description": "Nav item", "other": "Trang chủ"}}
+}`)},
+		"translate.ru.yaml": {Data: []byte(`
+- id: PersonCats
+  one: "У {{.Name}} {{.Count}} кошка."
+  few: "У {{.Name}} {{.Count}} кошки."
+  many: "У {{.Name}} {{.Count}} кошек."
+  other: "У {{.Name}} {{.Count}} кошки."
+`)},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: ".", GoI18nCompat: true, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 3, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	msg := i18n.(*Goi18n).messagesStore["en"]["PersonCats"]
+	if e, v := "The number of cats a person has", msg.Description; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		count         interface{}
+		expected      string
+	}{
+		{"en", "HelloWorld", nil, "Hello World!"},
+		{"vi", "HelloWorld", nil, "Chào thế giới!"},
+		{"en", "PersonCats", 1, "Nick has 1 cat."},
+		{"en", "PersonCats", 2, "Nick has 2 cats."},
+		{"vi", "PersonCats", 2, "Nick có 2 con mèo."},
+		{"ru", "PersonCats", 3, "У Nick 3 кошки."},
+		{"ru", "PersonCats", 5, "У Nick 5 кошек."},
+		{"en", "Custom", nil, "Hi Nick, {{not a placeholder}}"},
+		{"en", "nav.home", nil, "Home"},
+		{"vi", "nav.home", nil, "Trang chủ"},
+	}
+	for _, tc := range testCases {
+		cfg := LocalizeConfig{PluralCount: tc.count, TemplateData: map[string]interface{}{"Name": "Nick", "Count": tc.count}}
+		if v := i18n.Localize(tc.locale, tc.msgId, cfg); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+	// positional params are mapped to placeholders with custom delimiters
+	if e, v := "Hi Nick, {{not a placeholder}}", i18n.Localize("en", "Custom", "Nick"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	i18n, err = BuildI18nFromBytes([]byte(`{"HelloWorld": "Hallo Welt!"}`), I18nOptions{GoI18nCompat: true, DefaultLocale: "de"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Hallo Welt!", i18n.Localize("de", "HelloWorld"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	for _, invalid := range []string{`[{"other": "no id"}]`, `{"count": 1}`} {
+		if _, err := BuildI18nFromBytes([]byte(invalid), I18nOptions{GoI18nCompat: true, I18nFileFormat: Json, DefaultLocale: "en"}); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
@@ -2,26 +2,43 @@
 package goyai
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/btnguyen2k/consu/reddo"
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	// Version of goyai package
-	Version = "0.2.0"
+	Version = "0.3.0"
 )
 
 // LocaleInfo captures info of a locale package.
 type LocaleInfo struct {
-	Id          string
+	// Id is the locale's identity, which is the canonical form of the locale's BCP 47 language tag (e.g. "en-US").
+	// If the locale is not a well-formed BCP 47 language tag, Id is the locale as defined in the language file.
+	Id string
+
 	DisplayName string
+
+	// Tag is the parsed BCP 47 language tag of the locale. It is zero-value if the locale is not a well-formed
+	// BCP 47 language tag.
+	//
+	// Available since v0.3.0
+	Tag LocaleTag
 }
 
 // LocalizeConfig configures how a message should be localised, used by function I18n.Localize.
@@ -29,10 +46,15 @@ type LocalizeConfig struct {
 	// TemplateData is used to transform the message's template.
 	TemplateData map[string]interface{}
 
-	// PluralCount determines which plural form of the message is used. PluralCount must be an integer or nil.
-	// See Message for more information.
+	// PluralCount determines which plural form of the message is used. PluralCount must be a number (integer, floating
+	// point or big number), a string representing a decimal number (e.g. "1.0", "1,000") or nil. See Message for more information.
+	//
+	// The plural form is picked using the CLDR cardinal plural rules of the locale (e.g. for English, 1 is "one" and
+	// all other numbers, including "1.0" and 1.5, are "other"; for Russian, 2-4 are "few"). Visible fraction digits are
+	// taken into account, use a string to preserve trailing zeros (e.g. "1.0"). If PluralCount is nil or not a number,
+	// the "other" form is chosen. If the message does not have content for the picked form, the "other" form is used.
 	//
-	// Rule for picking plural form:
+	// Rule for picking plural form if I18nOptions.LegacyPluralRules is enabled:
 	// - if PluralCount is negative number, nil or not cast-able to integer, the "other" form is chosen.
 	// - if PluralCount = 0, the "zero" form is chosen.
 	// - if PluralCount = 1, one of "one"/"few"/"other" forms is chosen, priority is from left to right (e.g. "one" form has the highest priority, if absent, the next one is checked)
@@ -40,6 +62,21 @@ type LocalizeConfig struct {
 	// - if PluralCount > 2, one of "many"/"other" forms is chosen, priority is from left to right (e.g. "many" form has the highest priority, if absent, the next one is checked)
 	PluralCount interface{}
 
+	// OrdinalCount, if specified, determines which plural form of the message is used following the CLDR ordinal
+	// plural rules of the locale (e.g. for English, 1, 21, 31... are "one"; 2, 22, 32... are "two"; 3, 23, 33... are
+	// "few"; all others are "other"). OrdinalCount accepts the same values as PluralCount, and takes precedence over PluralCount.
+	//
+	// Available since v0.3.0
+	OrdinalCount interface{}
+
+	// Select picks a select variant of the message (e.g. "female", "formal", see Message.Variants), falling back to the
+	// message's content (the variant "other") if the message has no such variant. Plural forms are then picked from the
+	// selected variant. For messages in ICU MessageFormat, Select is the value of select arguments that are not in
+	// TemplateData.
+	//
+	// Available since v0.3.0
+	Select string
+
 	// DefaultMessage holds the default message where there is no localized one.
 	DefaultMessage string
 }
@@ -50,6 +87,13 @@ type I18n interface {
 	// Note: params can be mixed of strings, numbers and booleans but not LocalizeConfig instances. If there is one or more
 	// LocalizeConfig instances are supplied, only the first one is used; all other params are ignored (including
 	// strings/numbers/booleans and other LocalizeConfig instances).
+	//
+	// Since v0.3.0, locale is matched in canonical BCP 47 form, e.g. "en-US", "en_US" and "EN-us" are the same locale.
+	//
+	// Strings/numbers/booleans params are mapped to the message's placeholders in order of their first appearance in the
+	// message. Since v0.3.0, positional placeholders {{._0}}, {{._1}}... (e.g. converted from MessageFormat arguments
+	// "{0}", "{1}"... of .properties files) are mapped to the params at the same position, e.g. params "a", "b" render
+	// "{{._1}}-{{._0}}" as "b-a".
 	Localize(locale, msgId string, params ...interface{}) string
 
 	// Localise is alias of Localize.
@@ -73,23 +117,163 @@ const (
 
 	// Yaml hints that the file is YAML-encoded.
 	Yaml
+
+	// Po hints that the file is a gettext PO file (".po"). See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	Po
+
+	// Mo hints that the file is a compiled gettext MO file (".mo"). See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	Mo
+
+	// Xliff hints that the file is a XLIFF 1.2 or 2.0 file (".xlf", ".xliff"). See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	Xliff
+
+	// Toml hints that the file is TOML-encoded (".toml").
+	//
+	// Available since v0.3.0
+	Toml
+
+	// Properties hints that the file is a Java .properties file (".properties"). See function BuildI18n for more
+	// information.
+	//
+	// Available since v0.3.0
+	Properties
+
+	// AndroidStrings hints that the file is an Android string resource file (".xml", e.g. "res/values-vi/strings.xml").
+	// See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	AndroidStrings
+
+	// AppleStrings hints that the file is an Apple .strings file (".strings"). See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	AppleStrings
+
+	// AppleStringsdict hints that the file is an Apple .stringsdict file (".stringsdict"). See function BuildI18n for more
+	// information.
+	//
+	// Available since v0.3.0
+	AppleStringsdict
+
+	// I18next hints that the file is an i18next JSON resource file (".json"). i18next files are not auto-detected, the
+	// format must be specified explicitly. See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	I18next
+
+	// Arb hints that the file is a Flutter ARB file (".arb"). See function BuildI18n for more information.
+	//
+	// Available since v0.3.0
+	Arb
 )
 
 var (
 	// ErrInvalidFileFormat indicates that the specified language file format is not supported.
 	ErrInvalidFileFormat = errors.New("language file format is invalid or not supported")
+
+	// ErrExportNotSupported indicates that the I18n instance does not support exporting messages (e.g. it is not
+	// built by goyai).
+	//
+	// Available since v0.3.0
+	ErrExportNotSupported = errors.New("exporting messages is not supported by the I18n instance")
 )
 
 // I18nOptions specifies options to build new I18n instances.
 type I18nOptions struct {
 	// ConfigFileOrDir points to the configuration file or the directory where configuration files are located.
+	// Since v0.3.0, ConfigFileOrDir can also be a glob pattern (e.g. "languages/*.yaml", see path.Match for the syntax)
+	// matching configuration files.
 	ConfigFileOrDir string
 
+	// FS, if specified, is the file system where configuration files are loaded from (e.g. an embed.FS, a zip.Reader or
+	// a fstest.MapFS). ConfigFileOrDir is then a slash-separated path within FS (see fs.ValidPath), or a glob pattern.
+	// If FS is nil, configuration files are loaded from the OS file system.
+	//
+	// Available since v0.3.0
+	FS fs.FS
+
 	// DefaultLocale is the default locale to be used when non specified.
 	DefaultLocale string
 
+	// FallbackLocales maps a locale to the list of locales where a message is looked up, in order, when the message is
+	// missing in the locale (e.g. {"pt-BR": {"pt-PT"}}).
+	//
+	// A message is looked up in the following order: the requested locale, its fallback locales, its parent locales
+	// (derived by removing subtags from the end, e.g. "pt-BR" → "pt", "zh-Hant-TW" → "zh-Hant" → "zh") and finally
+	// the default locale. Fallback locales and the default locale are also followed by their own fallback/parent locales.
+	//
+	// Available since v0.3.0
+	FallbackLocales map[string][]string
+
 	// I18nFileFormat hints the format of configuration files.
 	I18nFileFormat I18nFileFormat
+
+	// Recursive, if true, loads configuration files in subdirectories of ConfigFileOrDir too, when ConfigFileOrDir is a
+	// directory (e.g. "locales/en/checkout.yaml", "locales/vi/checkout.yaml").
+	//
+	// Files are loaded in lexical order of their paths, directories before their contents. If a message is defined in
+	// more than one file, the last loaded one wins.
+	//
+	// Available since v0.3.0
+	Recursive bool
+
+	// IncludePatterns, if not empty, restricts configuration files loaded from a directory to those matching at least
+	// one of the patterns. ExcludePatterns skips configuration files, and directories when Recursive is true, matching
+	// any of the patterns.
+	//
+	// A pattern follows path.Match syntax and is matched against both the slash-separated path of a file relative to
+	// ConfigFileOrDir (e.g. "en/*.yaml") and the base name of the file (e.g. "*.yaml").
+	//
+	// Available since v0.3.0
+	IncludePatterns, ExcludePatterns []string
+
+	// LocaleFromFileName, if true, infers the locale of each configuration file from its file name or directory
+	// instead of top level keys, and the content of the file is the message map of the locale. The locale is, in order:
+	//   - the file name without extension (e.g. "en.yaml", "web/pt-BR.json"), or its last dot-separated segment if the
+	//     file name contains dots (e.g. "messages.vi.json");
+	//   - if the file name is not a locale, the nearest directory under ConfigFileOrDir that is a locale (e.g.
+	//     "vi/errors.yaml").
+	// A name is a locale if its language subtag is a known language (having CLDR plural rules), e.g. "web" is not.
+	//
+	// Available since v0.3.0
+	LocaleFromFileName bool
+
+	// LegacyPluralRules, if true, picks plural forms using the fixed count-to-form mapping of goyai v0.2.x instead of
+	// the CLDR plural rules of the locale. See LocalizeConfig.PluralCount for more information.
+	//
+	// Available since v0.3.0
+	LegacyPluralRules bool
+
+	// GoI18nCompat, if true, loads JSON, YAML and TOML files as go-i18n v2 message files (e.g. "active.vi.toml"), to
+	// migrate from github.com/nicksnyder/go-i18n without rewriting files:
+	//   - each file contains messages of one locale, inferred from its file name (e.g. "active.vi.toml", "vi.json") or
+	//     directory, or is DefaultLocale;
+	//   - a message is a string, or a map with keys "id", "description", "hash", "leftDelim", "rightDelim", "zero",
+	//     "one", "two", "few", "many" and "other" (case-insensitive); "id" overrides the message's key, "hash" is ignored,
+	//     "leftDelim"/"rightDelim" are custom delimiters of the message's templates (see Message.LeftDelim);
+	//   - ids of nested messages are joined with "." (e.g. {"nav": {"home": "Home"}} → "nav.home"), and a file can be an
+	//     array of messages with key "id".
+	//
+	// Available since v0.3.0
+	GoI18nCompat bool
+
+	// Strict, if true, validates all messages while building the I18n instance and fails with a *ValidationError
+	// listing all problems found in all language files (file, locale, message id and the problem), instead of failing
+	// at the first problem:
+	//   - language files that cannot be parsed;
+	//   - template syntax errors (e.g. "{{.name"), including ICU MessageFormat syntax errors;
+	//   - invalid message data (e.g. a plural form that is not a string, unknown keys);
+	//   - placeholders not referenced by the same message of DefaultLocale (e.g. a typo "{{.nmae}}" in a translation).
+	// If false, Go template syntax errors are logged as warnings and such messages are rendered as-is, as in v0.2.x.
+	//
+	// Available since v0.3.0
+	Strict bool
 }
 
 // NullI18n returns a "null" I18n instance.
@@ -98,157 +282,543 @@ func NullI18n() I18n {
 }
 
 // BuildI18n builds an I18n instance from message file(s) and returns it.
+//
+// Since v0.3.0, gettext PO/MO files are supported. Each PO/MO file contains messages of one locale, specified by the
+// header "Language" (or inferred from the file name if I18nOptions.LocaleFromFileName is true). msgid is the message
+// id (see PoContextSeparator for messages with msgctxt), plural translations msgstr[n] are mapped to CLDR plural forms
+// using the header "Plural-Forms", and extracted comments ("#. ...") are the message's description. Fuzzy entries and
+// entries without translation are ignored.
+//
+// Since v0.3.0, XLIFF 1.2 and 2.0 files are supported. Messages of a XLIFF file are loaded from translations (target)
+// for the target language of the file, or from source texts for the source language if the file has no target language
+// (the locale inferred from the file name, if I18nOptions.LocaleFromFileName is true, overrides the target language).
+// The name (XLIFF 2.0), resname (XLIFF 1.2) or id of a unit is the message id, units "<msg-id>[<plural-form>]"
+// (e.g. "files[one]") are plural forms of a message, and notes are the message's description. Texts of inline elements
+// are kept, placeholder elements <x> and <ph> are replaced with their text equivalents (attribute equiv-text or equiv).
+//
+// Since v0.3.0, Java .properties files are supported. Each .properties file contains messages of one locale, inferred
+// from the file name following the ResourceBundle naming convention (e.g. "messages_pt_BR.properties"). The locale of a
+// base bundle (e.g. "messages.properties") is inferred from the file name if I18nOptions.LocaleFromFileName is true,
+// or is I18nOptions.DefaultLocale. MessageFormat arguments "{0}", "{1}"... are converted to positional placeholders,
+// see LocalizeConfig.TemplateData.
+//
+// Since v0.3.0, Android string resource files (strings.xml) and Apple .strings/.stringsdict files are supported. Each
+// file contains messages of one locale, inferred from its directory: "values-<qualifier>" for Android (e.g.
+// "values-vi", "values-pt-rBR", "values-b+sr+Latn"; files in directories with other qualifiers, e.g. "values-night",
+// are ignored) and "<locale>.lproj" for Apple (e.g. "pt-BR.lproj"). The locale of default resources (e.g.
+// "values/strings.xml", "Base.lproj/Localizable.strings") is inferred from the file name if
+// I18nOptions.LocaleFromFileName is true, or is I18nOptions.DefaultLocale. Android <plurals> and .stringsdict plural
+// variants are mapped to plural forms of messages, items of an Android <string-array> are messages "<name>[0]",
+// "<name>[1]"..., comments are descriptions of messages, and printf-style format specifiers (e.g. "%1$s", "%@") are
+// converted to positional placeholders, see LocalizeConfig.TemplateData.
+//
+// Since v0.3.0, i18next JSON (v4) files and Flutter ARB files are supported. Each i18next file contains messages of one
+// locale, inferred from its path (e.g. "locales/vi/translation.json", "vi.json") or I18nOptions.DefaultLocale; nested
+// keys are joined with "." (e.g. "nav.home"), keys with plural suffixes (e.g. "item_one", "item_other") are plural forms
+// of a message, and interpolations "{{name}}" are converted to placeholders "{{.name}}". The locale of an ARB file is
+// its "@@locale" attribute, or is inferred from the file name (e.g. "app_vi.arb"); values are messages in ICU
+// MessageFormat (see Message.Syntax), and descriptions of "@key" metadata are descriptions of messages.
 func BuildI18n(opts I18nOptions) (I18n, error) {
 	switch opts.I18nFileFormat {
-	case Auto:
-		return buildI18nAuto(opts)
-	case Json:
-		return buildI18nJson(opts)
-	case Yaml:
-		return buildI18nYaml(opts)
+	case Auto, Json, Yaml, Po, Mo, Xliff, Toml, Properties, AndroidStrings, AppleStrings, AppleStringsdict, I18next, Arb:
+		return buildI18n(opts)
 	default:
 		return nil, ErrInvalidFileFormat
 	}
 }
 
-func buildI18nAuto(opts I18nOptions) (I18n, error) {
+// BuildI18nFromBytes builds an I18n instance from the content of a language file and returns it.
+//
+// opts.ConfigFileOrDir and opts.FS are ignored. If opts.I18nFileFormat is Auto, the format is detected from the content:
+// MO if the content starts with the MO magic number, JSON if the content starts with "{", Android string resources if the
+// content starts with "<" and has a <resources> element, Apple .stringsdict if the content starts with "<" and has a
+// <plist> element, XLIFF if the content starts with "<" otherwise, PO if the content has lines starting with "msgid",
+// YAML otherwise (ARB if JSON content has the attribute "@@locale"). TOML, .properties, .strings and i18next content is
+// not detected, opts.I18nFileFormat must be Toml, Properties, AppleStrings or I18next to load such content. Messages of
+// .properties, Android string resources, Apple .strings/.stringsdict and i18next content, and of ARB content without
+// "@@locale", belong to opts.DefaultLocale.
+//
+// Available since v0.3.0
+func BuildI18nFromBytes(data []byte, opts I18nOptions) (I18n, error) {
+	format := opts.I18nFileFormat
+	switch format {
+	case Auto:
+		format = sniffFileFormat(data)
+	case Json, Yaml, Po, Mo, Xliff, Toml, Properties, AndroidStrings, AppleStrings, AppleStringsdict, I18next, Arb:
+	default:
+		return nil, ErrInvalidFileFormat
+	}
 	localesStore := make(map[string]*LocaleInfo)
 	messagesStore := make(map[string]map[string]*Message)
+	locale := ""
+	if isSingleLocaleFormat(format, opts.GoI18nCompat) || format == Arb {
+		locale = opts.DefaultLocale
+	}
+	validationErr := &ValidationError{}
+	if err := loadLangContent(localesStore, messagesStore, data, format, locale, opts.GoI18nCompat); err != nil {
+		if !opts.Strict {
+			return nil, err
+		}
+		validationErr.add("", "", "", err)
+	}
+	return finishBuild(opts, localesStore, messagesStore, validationErr)
+}
 
-	fileOrDir := opts.ConfigFileOrDir
-	fileInfo, err := os.Stat(fileOrDir)
+// BuildI18nFromReader builds an I18n instance from the content of a language file read from r and returns it.
+//
+// See BuildI18nFromBytes for more information.
+//
+// Available since v0.3.0
+func BuildI18nFromReader(r io.Reader, opts I18nOptions) (I18n, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
+	return BuildI18nFromBytes(data, opts)
+}
+
+var rePoSniff = regexp.MustCompile(`(?m)^\s*msgid\s+"`)
+
+// sniffFileFormat detects the format of a language file from its content.
+func sniffFileFormat(data []byte) I18nFileFormat {
+	if len(data) >= 4 && (binary.LittleEndian.Uint32(data) == moMagic || binary.BigEndian.Uint32(data) == moMagic) {
+		return Mo
+	}
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(data, []byte("{")) {
+		if bytes.Contains(data, []byte(`"@@locale"`)) {
+			return Arb
+		}
+		return Json
+	}
+	if bytes.HasPrefix(data, []byte("<")) {
+		switch {
+		case bytes.Contains(data, []byte("<resources")):
+			return AndroidStrings
+		case bytes.Contains(data, []byte("<plist")):
+			return AppleStringsdict
+		}
+		return Xliff
+	}
+	if rePoSniff.Match(data) {
+		return Po
+	}
+	return Yaml
+}
 
-	if fileInfo.IsDir() {
-		// a directory contains multiple language files
-		files, err := ioutil.ReadDir(fileOrDir)
-		if err != nil {
+// osFS returns a file system rooted at the root directory of the OS path, and the slash-separated path relative to that root.
+func osFS(osPath string) (fs.FS, string, error) {
+	absPath, err := filepath.Abs(osPath)
+	if err != nil {
+		return nil, "", err
+	}
+	root := filepath.VolumeName(absPath) + string(filepath.Separator)
+	relPath := filepath.ToSlash(strings.TrimPrefix(absPath, root))
+	if relPath == "" {
+		relPath = "."
+	}
+	return os.DirFS(root), relPath, nil
+}
+
+func isGlobPattern(filePath string) bool {
+	return strings.ContainsAny(filePath, "*?[")
+}
+
+// detectFileFormat returns the format of a language file, detected by the file's extension.
+//
+// If format is not Auto, only files having the format's extension are accepted. If explicit is true (i.e. the file
+// is explicitly specified, not found in a directory) and format is not Auto, the file is accepted regardless of its extension.
+func detectFileFormat(filePath string, format I18nFileFormat, explicit bool) (I18nFileFormat, bool) {
+	if explicit && format != Auto {
+		return format, true
+	}
+	var detected I18nFileFormat
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".json":
+		if detected = Json; format == I18next {
+			detected = I18next
+		}
+	case ".yaml", ".yml":
+		detected = Yaml
+	case ".po":
+		detected = Po
+	case ".mo":
+		detected = Mo
+	case ".xlf", ".xliff":
+		detected = Xliff
+	case ".toml":
+		detected = Toml
+	case ".properties":
+		detected = Properties
+	case ".xml":
+		detected = AndroidStrings
+	case ".strings":
+		detected = AppleStrings
+	case ".stringsdict":
+		detected = AppleStringsdict
+	case ".arb":
+		detected = Arb
+	default:
+		return Auto, false
+	}
+	return detected, format == Auto || format == detected
+}
+
+func buildI18n(opts I18nOptions) (I18n, error) {
+	localesStore := make(map[string]*LocaleInfo)
+	messagesStore := make(map[string]map[string]*Message)
+
+	fsys, fileOrDir := opts.FS, opts.ConfigFileOrDir
+	if fsys == nil {
+		if fileOrDir == "" {
+			// do not load language files of the current working directory unintentionally
+			return nil, &fs.PathError{Op: "stat", Path: fileOrDir, Err: fs.ErrNotExist}
+		}
+		var err error
+		if fsys, fileOrDir, err = osFS(fileOrDir); err != nil {
 			return nil, err
 		}
-		for _, file := range files {
-			normFilename := strings.ToLower(file.Name())
-			if !file.IsDir() && (strings.HasSuffix(normFilename, ".yaml") || strings.HasSuffix(normFilename, ".yml")) {
-				if err := loadLangFileYaml(localesStore, messagesStore, fileOrDir, file); err != nil {
-					return nil, err
-				}
-			}
-			if !file.IsDir() && strings.HasSuffix(normFilename, ".json") {
-				if err := loadLangFileJson(localesStore, messagesStore, fileOrDir, file); err != nil {
-					return nil, err
-				}
+	}
+
+	validationErr := &ValidationError{}
+	load := func(filePath, relPath string, explicit bool) error {
+		err := loadLangFile(localesStore, messagesStore, fsys, filePath, relPath, opts, explicit)
+		if !opts.Strict {
+			if err != nil {
+				return fmt.Errorf("error loading language file [%s]: %w", filePath, err)
 			}
+			return nil
 		}
-	} else {
-		// a single language file
-		normFilename := strings.ToLower(fileInfo.Name())
-		if strings.HasSuffix(normFilename, ".yaml") || strings.HasSuffix(normFilename, ".yml") {
-			if err := loadLangFileYaml(localesStore, messagesStore, filepath.Dir(fileOrDir), fileInfo); err != nil {
-				return nil, err
-			}
+		// strict mode: collect problems of all files
+		if err != nil {
+			validationErr.add(filePath, "", "", err)
+		}
+		return nil
+	}
+
+	if isGlobPattern(fileOrDir) {
+		// language files matching a glob pattern
+		matches, err := fs.Glob(fsys, fileOrDir)
+		if err != nil {
+			return nil, err
 		}
-		if strings.HasSuffix(normFilename, ".json") {
-			if err := loadLangFileJson(localesStore, messagesStore, filepath.Dir(fileOrDir), fileInfo); err != nil {
+		for _, match := range matches {
+			if fileInfo, err := fs.Stat(fsys, match); err != nil {
 				return nil, err
+			} else if !fileInfo.IsDir() {
+				relPath := strings.TrimPrefix(match, globBaseDir(fileOrDir))
+				if err := load(match, relPath, false); err != nil {
+					return nil, err
+				}
 			}
 		}
+		return finishBuild(opts, localesStore, messagesStore, validationErr)
 	}
 
-	return &Goi18n{defaultLocale: opts.DefaultLocale, locales: localesStore, messagesStore: messagesStore}, nil
-}
-
-func buildI18nJson(opts I18nOptions) (I18n, error) {
-	localesStore := make(map[string]*LocaleInfo)
-	messagesStore := make(map[string]map[string]*Message)
-
-	fileOrDir := opts.ConfigFileOrDir
-	fileInfo, err := os.Stat(fileOrDir)
+	fileInfo, err := fs.Stat(fsys, fileOrDir)
 	if err != nil {
 		return nil, err
 	}
-
 	if fileInfo.IsDir() {
 		// a directory contains multiple language files
-		files, err := ioutil.ReadDir(fileOrDir)
+		err := fs.WalkDir(fsys, fileOrDir, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil || filePath == fileOrDir {
+				return err
+			}
+			relPath := strings.TrimPrefix(filePath, strings.TrimSuffix(fileOrDir, "/")+"/")
+			if entry.IsDir() {
+				if !opts.Recursive || matchAnyPattern(opts.ExcludePatterns, relPath) {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if len(opts.IncludePatterns) > 0 && !matchAnyPattern(opts.IncludePatterns, relPath) || matchAnyPattern(opts.ExcludePatterns, relPath) {
+				return nil
+			}
+			return load(filePath, relPath, false)
+		})
 		if err != nil {
 			return nil, err
 		}
-		for _, file := range files {
-			if !file.IsDir() && strings.HasSuffix(strings.ToLower(file.Name()), ".json") {
-				if err := loadLangFileJson(localesStore, messagesStore, fileOrDir, file); err != nil {
-					return nil, err
+	} else if err := load(fileOrDir, path.Base(fileOrDir), true); err != nil { // a single language file
+		return nil, err
+	}
+
+	return finishBuild(opts, localesStore, messagesStore, validationErr)
+}
+
+// finishBuild returns a new I18n instance with the loaded messages. In strict mode, placeholders of messages are
+// validated, and validationErr is returned if any problem is found.
+func finishBuild(opts I18nOptions, localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, validationErr *ValidationError) (I18n, error) {
+	// compile templates of all messages; messages with template syntax errors are rendered as-is, unless in strict mode
+	for locale, messages := range messagesStore {
+		for msgId, msg := range messages {
+			if _, err := msg.compile(); err != nil {
+				if opts.Strict {
+					validationErr.Problems = append(validationErr.Problems, ValidationProblem{File: msg.file, Locale: locale, MessageId: msgId, Problem: err.Error()})
+				} else {
+					log.Printf("[WARN] locale [%s]: %s", locale, err)
 				}
 			}
 		}
-	} else if err := loadLangFileJson(localesStore, messagesStore, filepath.Dir(fileOrDir), fileInfo); err != nil { // a single language file
-		return nil, err
 	}
+	if opts.Strict {
+		validationErr.Problems = append(validationErr.Problems, validatePlaceholders(messagesStore, canonicalLocale(opts.DefaultLocale))...)
+		if len(validationErr.Problems) > 0 {
+			validationErr.sort()
+			return nil, validationErr
+		}
+	}
+	return newGoi18n(opts, localesStore, messagesStore), nil
+}
+
+// matchAnyPattern returns true if the slash-separated path relPath, or its base name, matches any of the patterns.
+func matchAnyPattern(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, relPath); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(relPath)); ok {
+			return true
+		}
+	}
+	return false
+}
 
-	return &Goi18n{defaultLocale: opts.DefaultLocale, locales: localesStore, messagesStore: messagesStore}, nil
+// globBaseDir returns the leading directories of a glob pattern that do not contain any pattern character, with a
+// trailing slash (e.g. "locales/" for "locales/*/*.yaml").
+func globBaseDir(pattern string) string {
+	baseDir := ""
+	for _, dir := range strings.Split(path.Dir(pattern), "/") {
+		if isGlobPattern(dir) {
+			break
+		}
+		baseDir += dir + "/"
+	}
+	return baseDir
 }
 
-func loadLangFileJson(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, dirPath string, file os.FileInfo) error {
-	buf, err := ioutil.ReadFile(dirPath + "/" + file.Name())
+// loadLangFile loads a language file from a file system, see function detectFileFormat for parameters format and explicit.
+// Files of unsupported formats are ignored.
+//
+// relPath is the slash-separated path of the file relative to the loading directory, used to infer the file's locale
+// if opts.LocaleFromFileName is true.
+func loadLangFile(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, fsys fs.FS, filePath, relPath string, opts I18nOptions, explicit bool) error {
+	fileFormat, ok := detectFileFormat(filePath, opts.I18nFileFormat, explicit)
+	if !ok {
+		return nil
+	}
+	locale := ""
+	switch fileFormat {
+	case Properties:
+		locale, _ = propertiesLocale(relPath)
+	case AndroidStrings:
+		if locale, ok = androidLocale(relPath); !ok {
+			// resources for other configurations (e.g. "values-night")
+			return nil
+		}
+	case AppleStrings, AppleStringsdict:
+		locale, _ = appleLocale(relPath)
+	case I18next:
+		locale, _ = localeFromFilePath(relPath)
+	case Json, Yaml, Toml:
+		if opts.GoI18nCompat {
+			locale, _ = localeFromFilePath(relPath)
+		}
+	case Arb:
+		// fallback if the file has no "@@locale" attribute
+		locale, _ = propertiesLocale(relPath)
+	}
+	singleLocale := isSingleLocaleFormat(fileFormat, opts.GoI18nCompat)
+	if locale == "" && opts.LocaleFromFileName {
+		if locale, ok = localeFromFilePath(relPath); !ok && !singleLocale && fileFormat != Arb {
+			return fmt.Errorf("cannot infer locale from file path [%s]", filePath)
+		} else if !ok {
+			locale = ""
+		}
+	}
+	if locale == "" && singleLocale {
+		// base bundle, default resources
+		if locale = opts.DefaultLocale; locale == "" {
+			return fmt.Errorf("cannot infer locale from file path [%s]", filePath)
+		}
+	}
+	buf, err := fs.ReadFile(fsys, filePath)
 	if err != nil {
 		return err
 	}
-	var langData map[string]map[string]interface{}
-	if err := json.Unmarshal(buf, &langData); err != nil {
-		return err
+	// messages of the file are loaded separately, so that the file of each message is recorded (see ValidationProblem)
+	fileMessages := make(map[string]map[string]*Message)
+	err = loadLangContent(localesStore, fileMessages, buf, fileFormat, locale, opts.GoI18nCompat)
+	for fileLocale, messages := range fileMessages {
+		if messagesStore[fileLocale] == nil {
+			messagesStore[fileLocale] = make(map[string]*Message, len(messages))
+		}
+		for msgId, msg := range messages {
+			msg.file = filePath
+			messagesStore[fileLocale][msgId] = msg
+		}
 	}
-	return parseLangData(localesStore, messagesStore, langData)
+	return err
 }
 
-func buildI18nYaml(opts I18nOptions) (I18n, error) {
-	localesStore := make(map[string]*LocaleInfo)
-	messagesStore := make(map[string]map[string]*Message)
+// isSingleLocaleFormat returns true if files of the format contain messages of one locale that is not specified in
+// their content, but inferred from their path.
+func isSingleLocaleFormat(format I18nFileFormat, goI18nCompat bool) bool {
+	switch format {
+	case Properties, AndroidStrings, AppleStrings, AppleStringsdict, I18next:
+		return true
+	case Json, Yaml, Toml:
+		return goI18nCompat
+	}
+	return false
+}
 
-	fileOrDir := opts.ConfigFileOrDir
-	fileInfo, err := os.Stat(fileOrDir)
-	if err != nil {
-		return nil, err
+// isFileNameLocale returns true if a file name or directory name is a locale: a well-formed BCP 47 language tag whose
+// language subtag is a known language (e.g. "en", "pt-BR", "zh_Hant"; but not "web" or "app").
+func isFileNameLocale(name string) bool {
+	tag, err := ParseLocaleTag(name)
+	return err == nil && isKnownLanguage(tag.Language)
+}
+
+// isKnownLanguage returns true if lang is a language subtag having CLDR plural rules (e.g. "en", "vi").
+func isKnownLanguage(lang string) bool {
+	lang = strings.ToLower(lang)
+	if lang == "root" {
+		return false
 	}
+	_, cardinal := cardinalRulesTable[lang]
+	_, ordinal := ordinalRulesTable[lang]
+	return cardinal || ordinal
+}
 
-	if fileInfo.IsDir() {
-		// a directory contains multiple language files
-		files, err := ioutil.ReadDir(fileOrDir)
-		if err != nil {
-			return nil, err
-		}
-		for _, file := range files {
-			normFilename := strings.ToLower(file.Name())
-			if !file.IsDir() && (strings.HasSuffix(normFilename, ".yaml") || strings.HasSuffix(normFilename, ".yml")) {
-				if err := loadLangFileYaml(localesStore, messagesStore, fileOrDir, file); err != nil {
-					return nil, err
-				}
-			}
+// localeFromFilePath infers the locale of a language file from its slash-separated path relative to the loading
+// directory, in order:
+//   - the file name without extension (e.g. "en.yaml" or "web/en.yaml" → "en"), or its last dot-separated segment if
+//     the file name contains dots (e.g. "messages.vi.json" → "vi");
+//   - if the file name is not a locale, the nearest directory that is a locale (e.g. "vi/errors.yaml" or
+//     "vi/checkout/errors.yaml" → "vi").
+func localeFromFilePath(relPath string) (string, bool) {
+	name := path.Base(relPath)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	if pos := strings.LastIndex(name, "."); pos >= 0 {
+		name = name[pos+1:]
+	}
+	if isFileNameLocale(name) {
+		return name, true
+	}
+	for dir := path.Dir(relPath); dir != "." && dir != "/" && dir != ""; dir = path.Dir(dir) {
+		if locale := path.Base(dir); isFileNameLocale(locale) {
+			return locale, true
 		}
-	} else if err := loadLangFileYaml(localesStore, messagesStore, filepath.Dir(fileOrDir), fileInfo); err != nil { // a single language file
-		return nil, err
 	}
+	return name, false
+}
 
-	return &Goi18n{defaultLocale: opts.DefaultLocale, locales: localesStore, messagesStore: messagesStore}, nil
+// loadLangContent loads content of a language file in the specified format.
+//
+// If locale is not empty, the content is the message map of the locale. Otherwise, top level keys of the content are
+// locales mapped to their messages. If goI18nCompat is true, JSON, YAML and TOML content is a go-i18n message file (see
+// I18nOptions.GoI18nCompat).
+func loadLangContent(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, format I18nFileFormat, locale string, goI18nCompat bool) error {
+	if goI18nCompat && (format == Json || format == Yaml || format == Toml) {
+		return loadLangFileGoI18n(localesStore, messagesStore, buf, format, locale)
+	}
+	switch format {
+	case Json:
+		return loadLangFileJson(localesStore, messagesStore, buf, locale)
+	case Yaml:
+		return loadLangFileYaml(localesStore, messagesStore, buf, locale)
+	case Po:
+		return loadLangFilePo(localesStore, messagesStore, buf, locale)
+	case Mo:
+		return loadLangFileMo(localesStore, messagesStore, buf, locale)
+	case Xliff:
+		return loadLangFileXliff(localesStore, messagesStore, buf, locale)
+	case Toml:
+		return loadLangFileToml(localesStore, messagesStore, buf, locale)
+	case Properties:
+		return loadLangFileProperties(localesStore, messagesStore, buf, locale)
+	case AndroidStrings:
+		return loadLangFileAndroid(localesStore, messagesStore, buf, locale)
+	case AppleStrings:
+		return loadLangFileAppleStrings(localesStore, messagesStore, buf, locale)
+	case AppleStringsdict:
+		return loadLangFileAppleStringsdict(localesStore, messagesStore, buf, locale)
+	case I18next:
+		return loadLangFileI18next(localesStore, messagesStore, buf, locale)
+	case Arb:
+		return loadLangFileArb(localesStore, messagesStore, buf, locale)
+	}
+	return ErrInvalidFileFormat
 }
 
-func loadLangFileYaml(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, dirPath string, file os.FileInfo) error {
-	buf, err := ioutil.ReadFile(dirPath + "/" + file.Name())
-	if err != nil {
+func loadLangFileJson(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	var langData map[string]map[string]interface{}
+	if locale != "" {
+		var msgMap map[string]interface{}
+		if err := json.Unmarshal(buf, &msgMap); err != nil {
+			return err
+		}
+		langData = map[string]map[string]interface{}{locale: msgMap}
+	} else if err := json.Unmarshal(buf, &langData); err != nil {
 		return err
 	}
+	return parseLangData(localesStore, messagesStore, langData)
+}
+
+func loadLangFileToml(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
 	var langData map[string]map[string]interface{}
-	if err := yaml.Unmarshal(buf, &langData); err != nil {
+	if locale != "" {
+		var msgMap map[string]interface{}
+		if err := toml.Unmarshal(buf, &msgMap); err != nil {
+			return err
+		}
+		langData = map[string]map[string]interface{}{locale: msgMap}
+	} else if err := toml.Unmarshal(buf, &langData); err != nil {
 		return err
 	}
 	return parseLangData(localesStore, messagesStore, langData)
 }
 
+// loadLangFileYaml loads all documents of a YAML file, messages of later documents override earlier ones.
+func loadLangFileYaml(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	for docIndex := 1; ; docIndex++ {
+		var langData map[string]map[string]interface{}
+		var err error
+		if locale != "" {
+			var msgMap map[string]interface{}
+			if err = decoder.Decode(&msgMap); err == nil {
+				langData = map[string]map[string]interface{}{locale: msgMap}
+			}
+		} else {
+			err = decoder.Decode(&langData)
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error parsing YAML document #%d: %w", docIndex, err)
+		}
+		if err := parseLangData(localesStore, messagesStore, langData); err != nil {
+			return fmt.Errorf("error parsing YAML document #%d: %w", docIndex, err)
+		}
+	}
+}
+
+// parseLangData parses messages of locales into the stores. Messages that cannot be parsed are skipped, their problems
+// are returned as a *ValidationError.
 func parseLangData(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, langData map[string]map[string]interface{}) error {
+	validationErr := &ValidationError{}
 	// top level is "locale" mapped to messages
 	for locale, msgMap := range langData {
+		tag, err := ParseLocaleTag(locale)
+		if err == nil {
+			locale = tag.String()
+		}
 		localeInfo := localesStore[locale]
 		if localeInfo == nil {
-			localeInfo = &LocaleInfo{Id: locale, DisplayName: locale}
+			localeInfo = &LocaleInfo{Id: locale, DisplayName: locale, Tag: tag}
 			localesStore[locale] = localeInfo
 		}
 
@@ -258,21 +828,31 @@ func parseLangData(localesStore map[string]*LocaleInfo, messagesStore map[string
 			messagesStore[locale] = localizedMessages
 		}
 
+		// special message-id "_syntax": syntax of all messages of the locale (see Message.Syntax)
+		syntax, _ := reddo.ToString(msgMap["_syntax"])
 		for msgId, msgData := range msgMap {
 			// special message-id
 			if (msgId == "_display" || msgId == "_name") && (localeInfo.DisplayName == "" || localeInfo.DisplayName == localeInfo.Id) {
 				localeInfo.DisplayName, _ = reddo.ToString(msgData)
 				continue
 			}
+			if msgId == "_syntax" {
+				continue
+			}
 
 			// message-id mapped to message data, which is either simply a string or a struct
-			if msg, err := ParseMessage(msgId, msgData); err != nil {
-				return err
-			} else {
-				localizedMessages[msgId] = msg
+			msg := &Message{Id: msgId, Syntax: syntax}
+			if err := msg.parse(msgData); err != nil {
+				validationErr.add("", locale, msgId, err)
+				continue
 			}
+			localizedMessages[msgId] = msg
 		}
 	}
 
+	if len(validationErr.Problems) > 0 {
+		validationErr.sort()
+		return validationErr
+	}
 	return nil
 }
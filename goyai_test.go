@@ -1,8 +1,15 @@
 package goyai
 
 import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestNullI18n(t *testing.T) {
@@ -68,6 +75,27 @@ t2:
 `
 const yamlFile = "test_all_in_one.yaml"
 
+const tomlContent = `
+[en]
+_name = "English"
+hello = "Hello, world"
+hello_who = "Hello {{.name}}"
+
+[en.count]
+desc = "Demo plural forms"
+zero = "There is no item"
+One = "There is one item"
+TWO = "There is two items"
+Other = "Other cases"
+
+[vi]
+_name = "Tiếng Việt"
+hello = "Xin chào"
+
+[t1]
+`
+const tomlFile = "test_all_in_one.toml"
+
 const tempDir = "temp/"
 
 func _initDataJson() error {
@@ -96,6 +124,11 @@ func _initDataYaml() error {
 	return nil
 }
 
+func _initDataToml() error {
+	os.Mkdir(tempDir, 0711)
+	return ioutil.WriteFile(tempDir+tomlFile, []byte(tomlContent), 0644)
+}
+
 func TestBuildI18n_InvalidFormat(t *testing.T) {
 	testName := "TestBuildI18n_InvalidFormat"
 	i18n, err := BuildI18n(I18nOptions{I18nFileFormat: Auto - 1})
@@ -106,7 +139,7 @@ func TestBuildI18n_InvalidFormat(t *testing.T) {
 
 func TestBuildI18n_FileNotExists(t *testing.T) {
 	testName := "TestBuildI18n_FileNotExists"
-	for _, format := range []I18nFileFormat{Auto, Json, Yaml} {
+	for _, format := range []I18nFileFormat{Auto, Json, Yaml, Toml} {
 		i18n, err := BuildI18n(I18nOptions{I18nFileFormat: format, ConfigFileOrDir: "not-exists"})
 		if i18n != nil || err == nil {
 			t.Fatalf("%s failed", testName)
@@ -327,6 +360,43 @@ func TestGoi18n_Localize_Plural(t *testing.T) {
 	if i18n == nil || err != nil {
 		t.Fatalf("%s failed: %s", testName, err)
 	}
+	// CLDR plural rules for English: 1 is "one", others are "other"
+	expected := map[interface{}]string{"none": _other, -2: _other, -1: _one, 0: _other, 1: _one, 2: _other, 3: _other, 4: _other}
+	for k, e := range expected {
+		v := i18n.Localize("en", "count", &LocalizeConfig{PluralCount: k})
+		if v != e {
+			t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, k, e, v)
+		}
+	}
+}
+
+func TestGoi18n_Localize_Plural_Decimal(t *testing.T) {
+	testName := "TestGoi18n_Localize_Plural_Decimal"
+
+	os.RemoveAll(tempDir)
+	_initDataJson()
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + jsonFile, I18nFileFormat: Auto})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	expected := map[interface{}]string{1.5: _other, "1.0": _other, "1": _one, "1,000": _other, float32(1): _one}
+	for k, e := range expected {
+		v := i18n.Localize("en", "count", &LocalizeConfig{PluralCount: k})
+		if v != e {
+			t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, k, e, v)
+		}
+	}
+}
+
+func TestGoi18n_Localize_Plural_Legacy(t *testing.T) {
+	testName := "TestGoi18n_Localize_Plural_Legacy"
+
+	os.RemoveAll(tempDir)
+	_initDataJson()
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + jsonFile, I18nFileFormat: Auto, LegacyPluralRules: true})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
 	expected := map[interface{}]string{"none": _other, -2: _other, -1: _other, 0: _zero, 1: _one, 2: _two, 3: _other, 4: _other}
 	for k, e := range expected {
 		v := i18n.Localize("en", "count", &LocalizeConfig{PluralCount: k})
@@ -381,3 +451,566 @@ func TestGoi18n_Localize_Arbitrary(t *testing.T) {
 		}
 	}
 }
+
+func TestGoi18n_Localize_Ordinal(t *testing.T) {
+	testName := "TestGoi18n_Localize_Ordinal"
+
+	os.RemoveAll(tempDir)
+	_initDataJson()
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + jsonFile, I18nFileFormat: Auto})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	// CLDR ordinal rules for English: 1, 21, 31... are "one"; 2, 22, 32... are "two"; 3, 23, 33... are "few"
+	expected := map[interface{}]string{"none": _other, 1: _one, 2: _two, 3: _other, 11: _other, 12: _other, 21: _one, 22: _two}
+	for k, e := range expected {
+		v := i18n.Localize("en", "count", &LocalizeConfig{OrdinalCount: k})
+		if v != e {
+			t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, k, e, v)
+		}
+	}
+}
+
+const yamlContentFallback = `---
+en:
+  hello: "Hello"
+  bye: "Bye"
+  color: "color"
+  truck: "truck"
+pt:
+  hello: "Olá"
+  bye: "Tchau"
+pt-PT:
+  bye: "Adeus"
+pt-BR:
+  hello: "Oi"
+en-GB:
+  color: "colour"
+`
+
+func TestGoi18n_Localize_Fallback(t *testing.T) {
+	testName := "TestGoi18n_Localize_Fallback"
+
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	if err := ioutil.WriteFile(tempDir+yamlFile, []byte(yamlContentFallback), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + yamlFile, DefaultLocale: "en",
+		FallbackLocales: map[string][]string{"pt-BR": {"pt-PT"}, "en-AU": {"en-GB"}}})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	testCases := []struct{ locale, msgId, expected string }{
+		{"pt-BR", "hello", "Oi"},
+		{"pt-BR", "bye", "Adeus"},
+		{"pt-BR", "truck", "truck"},
+		{"pt-PT", "hello", "Olá"},
+		{"pt-MZ", "bye", "Tchau"},
+		{"pt", "bye", "Tchau"},
+		{"en-AU", "color", "colour"},
+		{"en-AU-x-test", "color", "colour"},
+		{"en-US", "color", "color"},
+		{"vi", "bye", "Bye"},
+		{"", "bye", "Bye"},
+	}
+	for _, testCase := range testCases {
+		if v := i18n.Localize(testCase.locale, testCase.msgId); v != testCase.expected {
+			t.Fatalf("%s failed: locale [%s] / msg-id [%s] / expected [%s] but received [%s]", testName, testCase.locale, testCase.msgId, testCase.expected, v)
+		}
+	}
+}
+
+func TestGoi18n_Localize_CanonicalLocale(t *testing.T) {
+	testName := "TestGoi18n_Localize_CanonicalLocale"
+
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	content := "en_us:\n  _name: English (US)\n  hello: Hello\nEN-us:\n  bye: Bye\nzh-hant-tw:\n  hello: 你好\n"
+	if err := ioutil.WriteFile(tempDir+yamlFile, []byte(content), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + yamlFile, DefaultLocale: "EN_US"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	locales := i18n.AvailableLocales()
+	if e, v := 2, len(locales); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := "en-US", locales[0].Id; v != e {
+		t.Fatalf("%s failed, expected locale [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "US", locales[0].Tag.Region; v != e {
+		t.Fatalf("%s failed, expected region [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "zh-Hant-TW", locales[1].Id; v != e {
+		t.Fatalf("%s failed, expected locale [%s] but received [%s]", testName, e, v)
+	}
+	for _, locale := range []string{"en-US", "en_us", "EN-US", ""} {
+		if e, v := "Hello", i18n.Localize(locale, "hello"); v != e {
+			t.Fatalf("%s failed: locale [%s] / expected [%s] but received [%s]", testName, locale, e, v)
+		}
+		if e, v := "Bye", i18n.Localize(locale, "bye"); v != e {
+			t.Fatalf("%s failed: locale [%s] / expected [%s] but received [%s]", testName, locale, e, v)
+		}
+	}
+	if e, v := "你好", i18n.Localize("ZH_hant_tw", "hello"); v != e {
+		t.Fatalf("%s failed: expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestGoi18n_MatchLocale(t *testing.T) {
+	testName := "TestGoi18n_MatchLocale"
+
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	if err := ioutil.WriteFile(tempDir+yamlFile, []byte(yamlContentFallback), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + yamlFile, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	testCases := []struct {
+		preferred  []string
+		locale     string
+		confidence Confidence
+	}{
+		{[]string{"pt-BR"}, "pt-BR", ConfidenceExact},
+		{[]string{"vi", "pt-AO"}, "pt", ConfidenceHigh},
+		{[]string{"vi, pt-AO;q=0.5, en-GB;q=0.8"}, "en-GB", ConfidenceExact},
+		{[]string{"fr-FR, fr;q=0.9", "en-US"}, "en", ConfidenceHigh},
+		{[]string{"vi, *;q=0.5"}, "en", ConfidenceLow},
+		{[]string{"vi"}, "en", ConfidenceNone},
+		{nil, "en", ConfidenceNone},
+	}
+	for _, testCase := range testCases {
+		locale, confidence := MatchLocale(i18n, testCase.preferred...)
+		if locale.Id != testCase.locale || confidence != testCase.confidence {
+			t.Fatalf("%s failed (%v), expected [%s/%s] but received [%s/%s]", testName, testCase.preferred, testCase.locale, testCase.confidence, locale.Id, confidence)
+		}
+	}
+
+	if locale, confidence := MatchLocale(NullI18n(), "en", "*"); locale.Id != "" || confidence != ConfidenceNone {
+		t.Fatalf("%s failed, expected no match but received [%s/%s]", testName, locale.Id, confidence)
+	}
+}
+
+func TestBuildI18n_FS(t *testing.T) {
+	testName := "TestBuildI18n_FS"
+	fsys := fstest.MapFS{
+		"languages/" + jsonFile:     {Data: []byte(jsonContent)},
+		"languages/" + yamlFile:     {Data: []byte(yamlContent)},
+		"languages/readme.txt":      {Data: []byte("not a language file")},
+		"languages/sub/" + yamlFile: {Data: []byte("vi:\n  hello: Xin chào\n")},
+		"single/messages.lang":      {Data: []byte(yamlContentFallback)},
+	}
+	testCases := []struct {
+		fileOrDir       string
+		format          I18nFileFormat
+		expectedLocales int
+	}{
+		{"languages", Auto, 4},
+		{"languages", Json, 3},
+		{"languages", Yaml, 3},
+		{"languages/" + jsonFile, Auto, 3},
+		{"languages/*.yaml", Auto, 3},
+		{"languages/*/*.yaml", Auto, 1},
+		{"languages/*", Auto, 4},
+		{"single/messages.lang", Yaml, 5},
+		{"single/messages.lang", Auto, 0},
+	}
+	for _, testCase := range testCases {
+		i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: testCase.fileOrDir, I18nFileFormat: testCase.format})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed (%s): %s", testName, testCase.fileOrDir, err)
+		}
+		if e, v := testCase.expectedLocales, len(i18n.AvailableLocales()); v != e {
+			t.Fatalf("%s failed (%s), expected %d available locales but received %d", testName, testCase.fileOrDir, e, v)
+		}
+	}
+
+	for _, fileOrDir := range []string{"not-exists", "/languages", "languages/[", "../languages"} {
+		if i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: fileOrDir}); i18n != nil || err == nil {
+			t.Fatalf("%s failed (%s): expected error", testName, fileOrDir)
+		}
+	}
+}
+
+func TestBuildI18n_FS_Zip(t *testing.T) {
+	testName := "TestBuildI18n_FS_Zip"
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range map[string]string{"i18n/" + jsonFile: jsonContent, "i18n/" + yamlFile: yamlContent} {
+		if w, err := zw.Create(name); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		} else if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: zr, ConfigFileOrDir: "i18n", DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 4, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := msgTextSimple, i18n.Localize("en", msgIdSimple); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestBuildI18n_GlobPattern(t *testing.T) {
+	testName := "TestBuildI18n_GlobPattern"
+
+	os.RemoveAll(tempDir)
+	_initDataJson()
+	_initDataYaml()
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + "*.json"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 3, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+}
+
+func TestBuildI18nFromBytes(t *testing.T) {
+	testName := "TestBuildI18nFromBytes"
+	testCases := []struct {
+		name    string
+		content string
+		format  I18nFileFormat
+	}{
+		{"json_auto", jsonContent, Auto},
+		{"json", jsonContent, Json},
+		{"yaml_auto", yamlContent, Auto},
+		{"yaml", yamlContent, Yaml},
+	}
+	for _, tc := range testCases {
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{I18nFileFormat: tc.format, DefaultLocale: "en2"})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed: %s / %s", testName+"/"+tc.name, i18n, err)
+		}
+		if e, v := "Hello, world", i18n.Localize("en", "hello"); v != e {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+tc.name, e, v)
+		}
+	}
+
+	if _, err := BuildI18nFromBytes([]byte(yamlContent), I18nOptions{I18nFileFormat: Json}); err == nil {
+		t.Fatalf("%s failed: expected error when parsing YAML content as JSON", testName)
+	}
+	if _, err := BuildI18nFromBytes([]byte(jsonContent), I18nOptions{I18nFileFormat: I18nFileFormat(99)}); err != ErrInvalidFileFormat {
+		t.Fatalf("%s failed: expected %s but received %s", testName, ErrInvalidFileFormat, err)
+	}
+}
+
+func TestBuildI18nFromReader(t *testing.T) {
+	testName := "TestBuildI18nFromReader"
+	i18n, err := BuildI18nFromReader(strings.NewReader(jsonContent), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 3, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+}
+
+const yamlContentMultiDoc = `---
+en:
+  _name: "English"
+  hello: "Hello"
+  bye: "Bye"
+---
+vi:
+  _name: "Tiếng Việt"
+  hello: "Xin chào"
+---
+en:
+  bye: "Goodbye"
+`
+
+func TestBuildI18n_YamlMultiDoc(t *testing.T) {
+	testName := "TestBuildI18n_YamlMultiDoc"
+	i18n, err := BuildI18nFromBytes([]byte(yamlContentMultiDoc), I18nOptions{I18nFileFormat: Yaml, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId, expected string
+	}{
+		{"en", "hello", "Hello"},
+		{"en", "bye", "Goodbye"},
+		{"vi", "hello", "Xin chào"},
+		{"vi", "bye", "Goodbye"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize(tc.locale, tc.msgId); v != tc.expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+tc.locale+"/"+tc.msgId, tc.expected, v)
+		}
+	}
+
+	_, err = BuildI18nFromBytes([]byte(yamlContentMultiDoc+"---\nen: [invalid\n"), I18nOptions{I18nFileFormat: Yaml})
+	if err == nil || !strings.Contains(err.Error(), "document #4") {
+		t.Fatalf("%s failed: expected error naming document #4 but received %s", testName, err)
+	}
+}
+
+func TestBuildI18n_Recursive(t *testing.T) {
+	testName := "TestBuildI18n_Recursive"
+	fsys := fstest.MapFS{
+		"locales/common.yaml":        {Data: []byte("en:\n  hello: Hello\n  bye: Bye\n")},
+		"locales/en/checkout.yaml":   {Data: []byte("en:\n  pay: Pay now\n")},
+		"locales/vi/checkout.yaml":   {Data: []byte("vi:\n  pay: Thanh toán\n")},
+		"locales/vi/common.yaml":     {Data: []byte("vi:\n  hello: Xin chào\n")},
+		"locales/vi/draft/bye.yaml":  {Data: []byte("vi:\n  bye: Tạm biệt\n")},
+		"locales/en/override.yaml":   {Data: []byte("en:\n  bye: Goodbye\n")},
+		"locales/en/checkout.yaml~":  {Data: []byte("en:\n  pay: Backup\n")},
+		"locales/en/notes/README.md": {Data: []byte("# notes")},
+	}
+	testCases := []struct {
+		name             string
+		opts             I18nOptions
+		locale, msgId, e string
+	}{
+		{"non_recursive", I18nOptions{}, "en", "pay", ""},
+		{"recursive", I18nOptions{Recursive: true}, "en", "pay", "Pay now"},
+		{"recursive_order", I18nOptions{Recursive: true}, "en", "bye", "Goodbye"},
+		{"recursive_nested", I18nOptions{Recursive: true}, "vi", "bye", "Tạm biệt"},
+		{"include", I18nOptions{Recursive: true, IncludePatterns: []string{"checkout.*"}}, "vi", "hello", ""},
+		{"include_rel_path", I18nOptions{Recursive: true, IncludePatterns: []string{"vi/*"}}, "vi", "pay", "Thanh toán"},
+		{"exclude_file", I18nOptions{Recursive: true, ExcludePatterns: []string{"override.yaml"}}, "en", "bye", "Bye"},
+		{"exclude_dir", I18nOptions{Recursive: true, ExcludePatterns: []string{"draft"}}, "vi", "bye", "Goodbye"},
+	}
+	for _, tc := range testCases {
+		tc.opts.FS, tc.opts.ConfigFileOrDir, tc.opts.DefaultLocale = fsys, "locales", "en"
+		i18n, err := BuildI18n(tc.opts)
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed: %s", testName+"/"+tc.name, err)
+		}
+		if v := i18n.Localize(tc.locale, tc.msgId); v != tc.e {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+tc.name, tc.e, v)
+		}
+	}
+}
+
+func TestLocaleFromFilePath(t *testing.T) {
+	testName := "TestLocaleFromFilePath"
+	testCases := []struct {
+		relPath, locale string
+		ok              bool
+	}{
+		{"en.yaml", "en", true},
+		{"pt_BR.json", "pt_BR", true},
+		{"messages.vi.json", "vi", true},
+		{"vi/messages.en.json", "en", true},
+		{"vi/errors.yaml", "vi", true},
+		{"vi/checkout/errors.yaml", "vi", true},
+		{"zh-Hant/checkout/pay.yaml", "zh-Hant", true},
+		{"messages.yaml", "messages", false},
+		{"common/messages.yaml", "messages", false},
+		{"messages.common.yaml", "common", false},
+		{"web/en.yaml", "en", true},
+		{"app/vi/en.yaml", "en", true},
+		{"vi/web.yaml", "vi", true},
+		{"vi/app.yaml", "vi", true},
+		{"web/messages.yaml", "messages", false},
+		{"vi/messages.common.yaml", "vi", true},
+	}
+	for _, tc := range testCases {
+		locale, ok := localeFromFilePath(tc.relPath)
+		if ok != tc.ok || ok && locale != tc.locale {
+			t.Fatalf("%s failed for [%s]: expected %#v/%v but received %#v/%v", testName, tc.relPath, tc.locale, tc.ok, locale, ok)
+		}
+	}
+}
+
+func TestBuildI18n_LocaleFromFileName(t *testing.T) {
+	testName := "TestBuildI18n_LocaleFromFileName"
+	fsys := fstest.MapFS{
+		"locales/en.yaml":             {Data: []byte("_name: English\nhello: Hello\nbye: Bye\n")},
+		"locales/messages.vi.json":    {Data: []byte(`{"_name": "Tiếng Việt", "hello": "Xin chào"}`)},
+		"locales/vi/checkout.yaml":    {Data: []byte("pay: Thanh toán\n")},
+		"locales/pt_BR/checkout.yaml": {Data: []byte("pay: Pagar\n")},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales", Recursive: true, LocaleFromFileName: true, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 3, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId, expected string
+	}{
+		{"en", "hello", "Hello"},
+		{"vi", "hello", "Xin chào"},
+		{"vi", "pay", "Thanh toán"},
+		{"vi", "bye", "Bye"},
+		{"pt-BR", "pay", "Pagar"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize(tc.locale, tc.msgId); v != tc.expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+tc.locale+"/"+tc.msgId, tc.expected, v)
+		}
+	}
+
+	i18n, err = BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales/*.*", LocaleFromFileName: true})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+
+	fsys["locales/messages.yaml"] = &fstest.MapFile{Data: []byte("hello: Hello\n")}
+	if _, err = BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales", LocaleFromFileName: true}); err == nil {
+		t.Fatalf("%s failed: expected error when locale cannot be inferred from file name", testName)
+	}
+
+	// directories that are not locales (e.g. "web", "app") do not override locales of file names
+	webFS := fstest.MapFS{
+		"i18n/web/en.yaml": {Data: []byte("hello: Hello web\n")},
+		"i18n/web/vi.yaml": {Data: []byte("hello: Xin chào web\n")},
+		"i18n/app/en.yaml": {Data: []byte("bye: Bye app\n")},
+	}
+	i18n, err = BuildI18n(I18nOptions{FS: webFS, ConfigFileOrDir: "i18n", Recursive: true, LocaleFromFileName: true, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	for _, tc := range []struct{ locale, msgId, expected string }{{"en", "hello", "Hello web"}, {"vi", "hello", "Xin chào web"}, {"en", "bye", "Bye app"}} {
+		if v := i18n.Localize(tc.locale, tc.msgId); v != tc.expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+tc.locale+"/"+tc.msgId, tc.expected, v)
+		}
+	}
+}
+
+func TestBuildI18n_SingleFile_TomlAuto(t *testing.T) {
+	testName := "TestBuildI18n_SingleFile_TomlAuto"
+
+	os.RemoveAll(tempDir)
+	_initDataToml()
+	for _, format := range []I18nFileFormat{Toml, Auto} {
+		i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + tomlFile, I18nFileFormat: format, DefaultLocale: "en"})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if e, v := 3, len(i18n.AvailableLocales()); v != e {
+			t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+		}
+		if e, v := "Tiếng Việt", i18n.(*Goi18n).locales["vi"].DisplayName; v != e {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+		}
+		testCases := []struct {
+			locale, msgId string
+			params        []interface{}
+			expected      string
+		}{
+			{"vi", "hello", nil, "Xin chào"},
+			{"vi", "hello_who", []interface{}{"Thanh"}, "Hello Thanh"},
+			{"en", "count", []interface{}{LocalizeConfig{PluralCount: 1}}, "There is one item"},
+			{"en", "count", []interface{}{LocalizeConfig{PluralCount: 2}}, "Other cases"},
+		}
+		for _, tc := range testCases {
+			if v := i18n.Localize(tc.locale, tc.msgId, tc.params...); v != tc.expected {
+				t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+			}
+		}
+	}
+
+	// TOML content must be loaded with explicit format
+	i18n, err := BuildI18nFromBytes([]byte(tomlContent), I18nOptions{I18nFileFormat: Toml})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if _, err := BuildI18nFromBytes([]byte("[en\nhello = 1"), I18nOptions{I18nFileFormat: Toml}); err == nil {
+		t.Fatalf("%s failed: expected error for invalid TOML", testName)
+	}
+
+	fsys := fstest.MapFS{
+		"locales/active.en.toml": {Data: []byte("hello = \"Hello\"\n[files]\none = \"{{.n}} file\"\nother = \"{{.n}} files\"\n")},
+	}
+	i18n, err = BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales", LocaleFromFileName: true})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "2 files", i18n.Localize("en", "files", LocalizeConfig{PluralCount: 2, TemplateData: map[string]interface{}{"n": 2}}); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestBuildI18n_TemplateSyntaxError(t *testing.T) {
+	testName := "TestBuildI18n_TemplateSyntaxError"
+	testCases := []struct {
+		content, msgId, expected string
+	}{
+		{`{"en": {"hello": "Hello {{.name|upper}}"}}`, "hello", "Hello {{.name|upper}}"},
+		{`{"en": {"count": {"one": "{{.count}} item", "other": "{{.count items"}}}`, "count", "{{.count items"},
+	}
+	for _, tc := range testCases {
+		// non-strict mode: messages with template syntax errors are rendered as-is
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{DefaultLocale: "en"})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed for %#v: %s", testName, tc.content, err)
+		}
+		if v := i18n.Localize("en", tc.msgId, LocalizeConfig{PluralCount: 2}); v != tc.expected {
+			t.Fatalf("%s failed for %#v: expected %#v but received %#v", testName, tc.content, tc.expected, v)
+		}
+		if _, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{DefaultLocale: "en", Strict: true}); err == nil || !strings.Contains(err.Error(), "error parsing message") {
+			t.Fatalf("%s failed: expected syntax error for %#v but received %v", testName, tc.content, err)
+		}
+	}
+	// ICU MessageFormat syntax errors fail in both modes
+	for _, strict := range []bool{false, true} {
+		content := "en:\n  _syntax: icu\n  hello: \"Hello {name\""
+		if _, err := BuildI18nFromBytes([]byte(content), I18nOptions{DefaultLocale: "en", Strict: strict}); err == nil || !strings.Contains(err.Error(), "error parsing message") {
+			t.Fatalf("%s failed: expected syntax error for %#v but received %v", testName, content, err)
+		}
+	}
+}
+
+func TestBuildI18n_LoadErrorFileName(t *testing.T) {
+	testName := "TestBuildI18n_LoadErrorFileName"
+	fsys := fstest.MapFS{
+		"i18n/en.yaml": {Data: []byte("en:\n  hello: Hello")},
+		"i18n/vi.json": {Data: []byte(`{"vi": {"hello": "Xin chào"`)},
+	}
+	_, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n", DefaultLocale: "en"})
+	if err == nil || !strings.Contains(err.Error(), "i18n/vi.json") {
+		t.Fatalf("%s failed: expected error with file name but received %v", testName, err)
+	}
+}
+
+func TestBuildI18n_EmptyConfigFileOrDir(t *testing.T) {
+	testName := "TestBuildI18n_EmptyConfigFileOrDir"
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	defer os.RemoveAll(tempDir)
+	// a stray language file in the current working directory must not be loaded
+	if err := ioutil.WriteFile(tempDir+"x.yaml", []byte("en:\n  hello: Hello\n"), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer os.Chdir(wd)
+	i18n, err := BuildI18n(I18nOptions{})
+	if i18n != nil || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("%s failed: expected error but received %v", testName, err)
+	}
+}
@@ -0,0 +1,139 @@
+package goyai
+
+import (
+	"net/http"
+)
+
+// LocaleResolver resolves the preferred locales of an HTTP request, used by HttpMiddleware.
+//
+// Available since v0.3.0
+type LocaleResolver struct {
+	// Resolve returns the preferred locales of the request, which can be BCP 47 language tags or Accept-Language header
+	// values (see function MatchLocale). It returns nil if the request does not specify any locale.
+	Resolve func(r *http.Request) []string
+
+	// Vary is the name of the request header the resolver depends on, which is added to the Vary response header.
+	// Leave it empty if the resolver does not depend on any request header.
+	Vary string
+}
+
+// LocaleFromQuery returns a LocaleResolver that resolves locale from a query parameter (e.g. "?locale=vi").
+//
+// Available since v0.3.0
+func LocaleFromQuery(param string) LocaleResolver {
+	return LocaleResolver{Resolve: func(r *http.Request) []string {
+		if value := r.URL.Query().Get(param); value != "" {
+			return []string{value}
+		}
+		return nil
+	}}
+}
+
+// LocaleFromCookie returns a LocaleResolver that resolves locale from a cookie.
+//
+// Available since v0.3.0
+func LocaleFromCookie(name string) LocaleResolver {
+	return LocaleResolver{Vary: "Cookie", Resolve: func(r *http.Request) []string {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			return []string{cookie.Value}
+		}
+		return nil
+	}}
+}
+
+// LocaleFromHeader returns a LocaleResolver that resolves locale from a request header (e.g. "X-Locale"). The header
+// value can be a single BCP 47 language tag or a list in Accept-Language format.
+//
+// Available since v0.3.0
+func LocaleFromHeader(name string) LocaleResolver {
+	return LocaleResolver{Vary: http.CanonicalHeaderKey(name), Resolve: func(r *http.Request) []string {
+		if value := r.Header.Get(name); value != "" {
+			return []string{value}
+		}
+		return nil
+	}}
+}
+
+// LocaleFromAcceptLanguage returns a LocaleResolver that resolves locale from the Accept-Language request header.
+//
+// Available since v0.3.0
+func LocaleFromAcceptLanguage() LocaleResolver {
+	return LocaleFromHeader("Accept-Language")
+}
+
+// HttpMiddlewareOptions specifies options to build HTTP middlewares, used by function HttpMiddleware.
+//
+// Available since v0.3.0
+type HttpMiddlewareOptions struct {
+	// LocaleResolvers determines the locale of a request. Resolvers are checked in order, the first one that resolves
+	// to an available locale wins. If none does, the default locale is used.
+	//
+	// Default value: [LocaleFromQuery("locale"), LocaleFromCookie("locale"), LocaleFromAcceptLanguage()]
+	LocaleResolvers []LocaleResolver
+}
+
+// HttpMiddleware returns a net/http middleware that resolves the locale of each request, stores a Localizer bound to
+// that locale in the request's context (use FromContext to retrieve it), and sets the Content-Language (only if the
+// locale is an available one) and Vary response headers.
+//
+// Available since v0.3.0
+func HttpMiddleware(i18n I18n, opts HttpMiddlewareOptions) func(http.Handler) http.Handler {
+	resolvers := opts.LocaleResolvers
+	if len(resolvers) == 0 {
+		resolvers = []LocaleResolver{LocaleFromQuery("locale"), LocaleFromCookie("locale"), LocaleFromAcceptLanguage()}
+	}
+	vary := make([]string, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		if resolver.Vary != "" && !containsString(vary, resolver.Vary) {
+			vary = append(vary, resolver.Vary)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := resolveLocale(i18n, resolvers, r)
+			if locale.Id != "" && isAvailableLocale(i18n, locale.Id) {
+				// the fallback default locale may have not been loaded, the response's language is unknown then
+				w.Header().Set("Content-Language", locale.Id)
+			}
+			for _, header := range vary {
+				w.Header().Add("Vary", header)
+			}
+			localizer := NewLocalizer(i18n, locale.Id)
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), localizer)))
+		})
+	}
+}
+
+func resolveLocale(i18n I18n, resolvers []LocaleResolver, r *http.Request) LocaleInfo {
+	for _, resolver := range resolvers {
+		if resolver.Resolve == nil {
+			continue
+		}
+		if preferred := resolver.Resolve(r); len(preferred) > 0 {
+			if locale, confidence := MatchLocale(i18n, preferred...); confidence != ConfidenceNone {
+				return locale
+			}
+		}
+	}
+	locale, _ := MatchLocale(i18n)
+	return locale
+}
+
+// isAvailableLocale returns true if locale is the id of an available locale of i18n.
+func isAvailableLocale(i18n I18n, locale string) bool {
+	for _, localeInfo := range i18n.AvailableLocales() {
+		if localeInfo.Id == locale {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
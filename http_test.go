@@ -0,0 +1,114 @@
+package goyai
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func _buildI18nFallback(t *testing.T, testName string) I18n {
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	if err := ioutil.WriteFile(tempDir+yamlFile, []byte(yamlContentFallback), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + yamlFile, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	return i18n
+}
+
+func TestHttpMiddleware(t *testing.T) {
+	testName := "TestHttpMiddleware"
+	i18n := _buildI18nFallback(t, testName)
+	handler := HttpMiddleware(i18n, HttpMiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).Localize("hello")))
+	}))
+	testCases := []struct {
+		query, cookie, acceptLanguage string
+		expectedLocale, expectedBody  string
+	}{
+		{"", "", "", "en", "Hello"},
+		{"", "", "vi, pt-BR;q=0.9", "pt-BR", "Oi"},
+		{"", "pt-PT", "pt-BR", "pt-PT", "Olá"},
+		{"pt-BR", "pt-PT", "en", "pt-BR", "Oi"},
+		{"vi", "ja", "pt", "pt", "Olá"},
+		{"vi", "", "ja", "en", "Hello"},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", "/?locale="+testCase.query, nil)
+		if testCase.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "locale", Value: testCase.cookie})
+		}
+		if testCase.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", testCase.acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if v := w.Header().Get("Content-Language"); v != testCase.expectedLocale {
+			t.Fatalf("%s failed (%#v), expected Content-Language [%s] but received [%s]", testName, testCase, testCase.expectedLocale, v)
+		}
+		if v := w.Body.String(); v != testCase.expectedBody {
+			t.Fatalf("%s failed (%#v), expected body [%s] but received [%s]", testName, testCase, testCase.expectedBody, v)
+		}
+		if e, v := []string{"Cookie", "Accept-Language"}, w.Header()["Vary"]; !reflect.DeepEqual(v, e) {
+			t.Fatalf("%s failed (%#v), expected Vary %#v but received %#v", testName, testCase, e, v)
+		}
+	}
+
+	// the default locale has not been loaded
+	i18n, err := BuildI18nFromBytes([]byte("vi:\n  hello: Xin chào\n"), I18nOptions{DefaultLocale: "fr_FR"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	handler = HttpMiddleware(i18n, HttpMiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).Locale()))
+	}))
+	for acceptLanguage, expected := range map[string]string{"vi": "vi", "ja": ""} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if v := w.Header().Get("Content-Language"); v != expected {
+			t.Fatalf("%s failed (%s), expected Content-Language [%s] but received [%s]", testName, acceptLanguage, expected, v)
+		}
+	}
+}
+
+func TestHttpMiddleware_CustomResolvers(t *testing.T) {
+	testName := "TestHttpMiddleware_CustomResolvers"
+	i18n := _buildI18nFallback(t, testName)
+	opts := HttpMiddlewareOptions{LocaleResolvers: []LocaleResolver{
+		LocaleFromHeader("x-locale"),
+		{Resolve: func(r *http.Request) []string { return []string{r.URL.Path[1:]} }},
+		{},
+	}}
+	handler := HttpMiddleware(i18n, opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		localizer := FromContext(r.Context())
+		w.Write([]byte(localizer.Locale() + ":" + localizer.Localise("bye")))
+	}))
+	testCases := map[string]string{"/pt-PT": "pt-PT:Adeus", "/vi": "en:Bye"}
+	for path, expected := range testCases {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if v := w.Body.String(); v != expected {
+			t.Fatalf("%s failed (%s), expected body [%s] but received [%s]", testName, path, expected, v)
+		}
+		if e, v := []string{"X-Locale"}, w.Header()["Vary"]; !reflect.DeepEqual(v, e) {
+			t.Fatalf("%s failed (%s), expected Vary %#v but received %#v", testName, path, e, v)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/vi", nil)
+	req.Header.Set("X-Locale", "pt-BR")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if e, v := "pt-BR:Tchau", w.Body.String(); v != e {
+		t.Fatalf("%s failed, expected body [%s] but received [%s]", testName, e, v)
+	}
+}
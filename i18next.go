@@ -0,0 +1,153 @@
+package goyai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// flattenI18next flattens nested objects (and arrays) of an i18next resource into keys joined with "."
+// (e.g. {"nav": {"home": "Home"}} → "nav.home").
+func flattenI18next(prefix string, value interface{}, result map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenI18next(prefix+key+".", child, result)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenI18next(prefix+strconv.Itoa(i)+".", child, result)
+		}
+	case string:
+		result[strings.TrimSuffix(prefix, ".")] = v
+	case nil:
+	default:
+		result[strings.TrimSuffix(prefix, ".")] = fmt.Sprint(v)
+	}
+}
+
+var (
+	reI18nextPluralKey     = regexp.MustCompile(`^(.+)_(zero|one|two|few|many|other)$`)
+	reI18nextInterpolation = regexp.MustCompile(`{{-?\s*([^{}\s,]+)\s*(?:,[^{}]*)?}}`)
+)
+
+// loadLangFileI18next loads messages of a locale from an i18next JSON (v4) resource file.
+//
+// Nested keys are joined with "." (e.g. "nav.home"), keys with plural suffixes "_zero", "_one", "_two", "_few",
+// "_many" and "_other" are plural forms of a message (e.g. "item_one" and "item_other" are plural forms of message
+// "item"), and interpolations (e.g. "{{name}}", "{{- html}}", "{{price, currency}}") are converted to placeholders
+// (e.g. "{{.name}}").
+func loadLangFileI18next(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing i18next file: locale is not specified")
+	}
+	var resources map[string]interface{}
+	if err := json.Unmarshal(buf, &resources); err != nil {
+		return fmt.Errorf("error parsing i18next file: %w", err)
+	}
+	flat := make(map[string]string)
+	flattenI18next("", resources, flat)
+	msgMap := make(map[string]interface{})
+	// keys without plural suffix first, so that "<key>_other" wins over "<key>"
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return !reI18nextPluralKey.MatchString(keys[i]) && reI18nextPluralKey.MatchString(keys[j])
+	})
+	for _, key := range keys {
+		if key == "_display" || key == "_name" {
+			msgMap[key] = flat[key]
+			continue
+		}
+		msgId, form := key, pluralOther
+		if match := reI18nextPluralKey.FindStringSubmatch(key); match != nil {
+			msgId, form = match[1], match[2]
+		}
+		msgData, ok := msgMap[msgId].(map[string]string)
+		if !ok {
+			msgData = make(map[string]string)
+			msgMap[msgId] = msgData
+		}
+		msgData[form] = reI18nextInterpolation.ReplaceAllString(flat[key], "{{.$1}}")
+	}
+	for msgId, msgData := range msgMap {
+		if data, ok := msgData.(map[string]string); ok && data[pluralOther] == "" {
+			// plural forms without form "other" are ignored
+			delete(msgMap, msgId)
+		}
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
+
+// ExportI18next writes messages of a locale as an i18next JSON (v4) resource file to w.
+//
+// Message ids are split by "." into nested keys (e.g. "nav.home" → {"nav": {"home": ...}}), plural forms are written
+// as keys with plural suffixes (e.g. "item_one", "item_other") and placeholders as interpolations (e.g. "{{name}}").
+// Messages in ICU MessageFormat (see Message.Syntax), with select variants (see Message.Variants) or with exact plural
+// forms (see Message.Exact) cannot be exported, an error is returned if there is any.
+//
+// Available since v0.3.0
+func ExportI18next(w io.Writer, i18n I18n, locale string) error {
+	_, messages, err := exportMessages(i18n, locale)
+	if err != nil {
+		return err
+	}
+	if err := checkNoIcuMessages("an i18next file", messages); err != nil {
+		return err
+	}
+	if err := checkNoVariants("an i18next file", messages); err != nil {
+		return err
+	}
+	if err := checkNoExact("an i18next file", messages); err != nil {
+		return err
+	}
+	resources := make(map[string]interface{})
+	for _, msg := range messages {
+		if !msg.hasPluralForms() {
+			putI18nextResource(resources, msg.Id, i18nextText(msg.Other))
+			continue
+		}
+		for _, category := range pluralCategories {
+			if text := msg.pluralForm(category); text != "" {
+				putI18nextResource(resources, msg.Id+"_"+category, i18nextText(text))
+			}
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(resources)
+}
+
+// putI18nextResource puts a value into nested resources at the path key. If a segment of the path is already a
+// value, the remaining segments are kept as a flat key, which i18next also resolves.
+func putI18nextResource(resources map[string]interface{}, key, value string) {
+	segments := strings.Split(key, ".")
+	current := resources
+	for i, segment := range segments[:len(segments)-1] {
+		next, exists := current[segment]
+		if !exists {
+			next = make(map[string]interface{})
+			current[segment] = next
+		}
+		nextMap, ok := next.(map[string]interface{})
+		if !ok {
+			current[strings.Join(segments[i:], ".")] = value
+			return
+		}
+		current = nextMap
+	}
+	current[segments[len(segments)-1]] = value
+}
+
+// i18nextText converts placeholders of a message template to i18next interpolations (e.g. "{{.name}}" → "{{name}}").
+func i18nextText(tpl string) string {
+	tpl = strings.ReplaceAll(tpl, templateLiteralBrace, "{")
+	return rePlaceholderToken.ReplaceAllString(tpl, "{{$1}}")
+}
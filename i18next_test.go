@@ -0,0 +1,147 @@
+package goyai
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+const i18nextContentEn = `{
+  "_name": "English",
+  "hello": "Hello {{name}}!",
+  "nav": {
+    "home": "Home",
+    "settings": {"title": "Settings"}
+  },
+  "item": "An item",
+  "item_one": "{{count}} item",
+  "item_other": "{{count}} items",
+  "price": "Price: {{val, currency}}",
+  "html": "Raw {{- html}}",
+  "planets": ["Mercury", "Venus"],
+  "max": 10
+}`
+
+func TestBuildI18n_I18next(t *testing.T) {
+	testName := "TestBuildI18n_I18next"
+	fsys := fstest.MapFS{
+		"locales/en/translation.json": {Data: []byte(i18nextContentEn)},
+		"locales/ru/translation.json": {Data: []byte(`{"item_one": "{{count}} предмет", "item_few": "{{count}} предмета", "item_many": "{{count}} предметов", "item_other": "{{count}} предмета"}`)},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "locales", Recursive: true, I18nFileFormat: I18next, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		count         interface{}
+		expected      string
+	}{
+		{"en", "hello", nil, "Hello Thanh!"},
+		{"en", "nav.home", nil, "Home"},
+		{"en", "nav.settings.title", nil, "Settings"},
+		{"en", "item", 1, "1 item"},
+		{"en", "item", 2, "2 items"},
+		{"ru", "item", 3, "3 предмета"},
+		{"ru", "item", 5, "5 предметов"},
+		{"en", "price", nil, "Price: $1"},
+		{"en", "html", nil, "Raw <b>"},
+		{"en", "planets.1", nil, "Venus"},
+		{"en", "max", nil, "10"},
+	}
+	for _, tc := range testCases {
+		cfg := LocalizeConfig{PluralCount: tc.count, TemplateData: map[string]interface{}{"name": "Thanh", "count": tc.count, "val": "$1", "html": "<b>"}}
+		if v := i18n.Localize(tc.locale, tc.msgId, cfg); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	// i18next files are not auto-detected
+	i18n, err = BuildI18nFromBytes([]byte(i18nextContentEn), I18nOptions{I18nFileFormat: I18next, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Home", i18n.Localize("en", "nav.home"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestExportI18next(t *testing.T) {
+	testName := "TestExportI18next"
+	yamlContentI18next := `
+en:
+  hello: Hello {{.name}} & <friends>
+  nav.home: Home
+  nav.settings: Settings
+  nav.settings.title: Settings title
+  item:
+    one: "{{.count}} item"
+    other: "{{.count}} items"
+`
+	i18n, err := BuildI18nFromBytes([]byte(yamlContentI18next), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := ExportI18next(buf, i18n, "en"); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	var resources map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &resources); err != nil {
+		t.Fatalf("%s failed: %s\n%s", testName, err, buf.String())
+	}
+	expected := map[string]interface{}{
+		"hello":      "Hello {{name}} & <friends>",
+		"nav":        map[string]interface{}{"home": "Home", "settings": "Settings", "settings.title": "Settings title"},
+		"item_one":   "{{count}} item",
+		"item_other": "{{count}} items",
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, resources)
+	}
+	exported, err := BuildI18nFromBytes(buf.Bytes(), I18nOptions{I18nFileFormat: I18next, DefaultLocale: "en"})
+	if exported == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for msgId := range i18n.(*Goi18n).messagesStore["en"] {
+		if msgId == "nav.settings.title" {
+			// flat keys inside nested objects are resolved by i18next, but loaded as nested keys by goyai
+			continue
+		}
+		for _, count := range []interface{}{nil, 1, 5} {
+			cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"name": "X", "count": count}}
+			if e, v := i18n.Localize("en", msgId, cfg), exported.Localize("en", msgId, cfg); v != e {
+				t.Fatalf("%s failed for [%s/%v]: expected %#v but received %#v", testName, msgId, count, e, v)
+			}
+		}
+	}
+
+	icu, err := BuildI18nFromBytes([]byte("en:\n  _syntax: icu\n  items: \"{count, plural, one {# item} other {# items}}\"\n"), I18nOptions{DefaultLocale: "en"})
+	if icu == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportI18next(new(bytes.Buffer), icu, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+
+	variants, err := BuildI18nFromBytes([]byte("en:\n  hello:\n    other: Hello\n    select:\n      female: Hi madam\n"), I18nOptions{DefaultLocale: "en"})
+	if variants == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportI18next(new(bytes.Buffer), variants, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+
+	exact, err := BuildI18nFromBytes([]byte("en:\n  files:\n    \"=0\": No files\n    one: One file\n    other: \"{{.count}} files\"\n"), I18nOptions{DefaultLocale: "en"})
+	if exact == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportI18next(new(bytes.Buffer), exact, "en"); err == nil {
+		t.Fatalf("%s failed: expected error for message with exact plural forms", testName)
+	}
+}
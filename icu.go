@@ -0,0 +1,563 @@
+package goyai
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+)
+
+// icuNode is a node of a parsed ICU MessageFormat pattern: icuText, icuArg, icuPound or *icuComplexArg.
+type icuNode interface{}
+
+// icuText is literal text (quotes already processed).
+type icuText string
+
+// icuArg is a simple argument, e.g. "{name}", "{0}" or "{amount, number, currency}".
+type icuArg struct {
+	name, argType, style string
+}
+
+// icuPound is "#" in a plural/selectordinal option, which is the number of the enclosing plural argument.
+type icuPound struct{}
+
+// icuComplexArg is a plural, selectordinal or select argument, e.g. "{count, plural, one{# file} other{# files}}".
+type icuComplexArg struct {
+	name, argType string
+	offset        int
+	options       []icuOption
+}
+
+// icuOption is an option of a complex argument: a keyword (e.g. "one", "other", "male") or an explicit value
+// (e.g. "=0") and its sub-message.
+type icuOption struct {
+	key   string
+	nodes []icuNode
+}
+
+// option returns the sub-message of the option with the specified key, or nil if not found.
+func (arg *icuComplexArg) option(key string) []icuNode {
+	for _, opt := range arg.options {
+		if opt.key == key {
+			return opt.nodes
+		}
+	}
+	return nil
+}
+
+// parseIcuMessage parses an ICU MessageFormat pattern. Apostrophes are processed as in ICU's default mode: two
+// consecutive apostrophes are a single apostrophe, an apostrophe followed by a syntax character ("{", "}", "|", or "#"
+// in a plural option) starts quoted literal text, which ends at the next single apostrophe, other apostrophes are
+// literal.
+func parseIcuMessage(pattern string) ([]icuNode, error) {
+	p := &icuParser{s: pattern}
+	nodes, err := p.parseNodes(false)
+	if err == nil && p.pos < len(p.s) {
+		err = p.errorf("unexpected '}'")
+	}
+	return nodes, err
+}
+
+type icuParser struct {
+	s   string
+	pos int
+}
+
+func (p *icuParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("error parsing ICU message at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseNodes parses a (sub-)message until the end of the pattern or an unmatched "}".
+func (p *icuParser) parseNodes(inPlural bool) ([]icuNode, error) {
+	nodes := make([]icuNode, 0)
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, icuText(text.String()))
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'':
+			text.WriteByte('\'')
+			p.pos += 2
+		case c == '\'' && p.pos+1 < len(p.s) && (strings.IndexByte("{}|", p.s[p.pos+1]) >= 0 || inPlural && p.s[p.pos+1] == '#'):
+			p.readQuoted(&text)
+		case c == '{':
+			flush()
+			node, err := p.parseArg(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, icuPound{})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+	return nodes, nil
+}
+
+// readQuoted reads quoted literal text, starting at the opening apostrophe.
+func (p *icuParser) readQuoted(text *strings.Builder) {
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		if p.s[p.pos] != '\'' {
+			text.WriteByte(p.s[p.pos])
+		} else if p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+		} else {
+			p.pos++
+			return
+		}
+	}
+}
+
+func (p *icuParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// readToken reads an argument name, argument type or option key.
+func (p *icuParser) readToken() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n,{}", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *icuParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+// parseArg parses an argument, starting at "{". inPlural is true if the argument is inside a plural/selectordinal
+// option, in which case "#" inside a select option still refers to the enclosing plural argument.
+func (p *icuParser) parseArg(inPlural bool) (icuNode, error) {
+	p.pos++
+	name := p.readToken()
+	if name == "" {
+		return nil, p.errorf("missing argument name")
+	}
+	if p.skipSpaces(); p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return icuArg{name: name}, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	argType := p.readToken()
+	if p.skipSpaces(); p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return p.simpleArg(name, argType, "")
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	switch argType {
+	case "plural", "selectordinal", "select":
+		return p.parseComplexArg(name, argType, inPlural)
+	}
+	// argument style, e.g. "currency" or "::compact-short", up to the matching "}"
+	start, depth := p.pos, 0
+	for ; p.pos < len(p.s); p.pos++ {
+		if p.s[p.pos] == '{' {
+			depth++
+		} else if p.s[p.pos] == '}' {
+			if depth == 0 {
+				p.pos++
+				return p.simpleArg(name, argType, strings.TrimSpace(p.s[start:p.pos-1]))
+			}
+			depth--
+		}
+	}
+	return nil, p.errorf("unterminated argument [%s]", name)
+}
+
+// simpleArg returns a simple argument, or an error if its type or style is not supported by the renderer (see function
+// formatIcuArg), e.g. "{price, number, currency}" or "{n, spellout}".
+func (p *icuParser) simpleArg(name, argType, style string) (icuNode, error) {
+	supported := false
+	switch argType {
+	case "number":
+		supported = style == "" || style == "integer" || style == "percent"
+	case "date":
+		_, supported = icuDateLayouts[style]
+		supported = supported || style == ""
+	case "time":
+		_, supported = icuTimeLayouts[style]
+		supported = supported || style == ""
+	default:
+		return nil, p.errorf("unsupported type [%s] of argument [%s]", argType, name)
+	}
+	if !supported {
+		return nil, p.errorf("unsupported style [%s] of %s argument [%s]", style, argType, name)
+	}
+	return icuArg{name: name, argType: argType, style: style}, nil
+}
+
+// parseComplexArg parses options of a plural, selectordinal or select argument, up to and including the closing "}".
+func (p *icuParser) parseComplexArg(name, argType string, inPlural bool) (icuNode, error) {
+	arg := &icuComplexArg{name: name, argType: argType}
+	for {
+		key := p.readToken()
+		if key == "" {
+			if p.pos < len(p.s) && p.s[p.pos] == '}' {
+				p.pos++
+				break
+			}
+			return nil, p.errorf("unterminated argument [%s]", name)
+		}
+		if strings.HasPrefix(key, "offset:") && argType != "select" {
+			value := strings.TrimPrefix(key, "offset:")
+			if value == "" {
+				value = p.readToken()
+			}
+			offset, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, p.errorf("invalid offset [%s]", value)
+			}
+			arg.offset = offset
+			continue
+		}
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+		nodes, err := p.parseNodes(inPlural || argType != "select")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+		arg.options = append(arg.options, icuOption{key: key, nodes: nodes})
+	}
+	if arg.option(pluralOther) == nil {
+		return nil, p.errorf("missing option 'other' of argument [%s]", name)
+	}
+	return arg, nil
+}
+
+// icuPlaceholder returns the message template placeholder of an ICU argument name; numbered arguments (e.g. "{0}")
+// are positional placeholders (e.g. "{{._0}}"), see LocalizeConfig.TemplateData.
+func icuPlaceholder(name string) string {
+	if _, err := strconv.Atoi(name); err == nil {
+		return "{{._" + name + "}}"
+	}
+	return "{{." + name + "}}"
+}
+
+// icuArgName returns the ICU argument name of a placeholder token, the reverse of icuPlaceholder (e.g. "name" → "name",
+// "_0" → "0").
+func icuArgName(token string) string {
+	if _, err := strconv.Atoi(strings.TrimPrefix(token, "_")); err == nil && strings.HasPrefix(token, "_") {
+		return token[1:]
+	}
+	return token
+}
+
+// templateToIcu converts a message template to an ICU MessageFormat pattern: placeholders are converted to simple
+// arguments (positional placeholders, e.g. "{{._0}}", to numbered arguments, e.g. "{0}"), and syntax characters of
+// text are quoted.
+func templateToIcu(tpl string, inPlural bool) string {
+	tpl = strings.ReplaceAll(tpl, templateLiteralBrace, "{")
+	replacer := strings.NewReplacer("'", "''", "{", "'{'", "}", "'}'")
+	if inPlural {
+		replacer = strings.NewReplacer("'", "''", "{", "'{'", "}", "'}'", "#", "'#'")
+	}
+	var sb strings.Builder
+	last := 0
+	for _, loc := range rePlaceholderToken.FindAllStringSubmatchIndex(tpl, -1) {
+		sb.WriteString(replacer.Replace(tpl[last:loc[0]]))
+		sb.WriteString("{" + icuArgName(tpl[loc[2]:loc[3]]) + "}")
+		last = loc[1]
+	}
+	sb.WriteString(replacer.Replace(tpl[last:]))
+	return sb.String()
+}
+
+// icuArgNames appends names of arguments of parsed ICU nodes to names, in order of first appearance.
+func icuArgNames(nodes []icuNode, names []string) []string {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case icuArg:
+			if !containsString(names, n.name) {
+				names = append(names, n.name)
+			}
+		case *icuComplexArg:
+			if !containsString(names, n.name) {
+				names = append(names, n.name)
+			}
+			for _, opt := range n.options {
+				names = icuArgNames(opt.nodes, names)
+			}
+		}
+	}
+	return names
+}
+
+// icuRenderer renders parsed ICU nodes of a message with the values of a LocalizeConfig.
+type icuRenderer struct {
+	plural  *pluralSelector
+	cfg     *LocalizeConfig
+	symbols icuNumberSymbols
+}
+
+// value returns the value of an argument from cfg.TemplateData. Numbered arguments (e.g. "{0}") are looked up as
+// positional placeholders first (e.g. "_0").
+func (r *icuRenderer) value(name string) (interface{}, bool) {
+	if r.cfg == nil || r.cfg.TemplateData == nil {
+		return nil, false
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		if v, ok := r.cfg.TemplateData["_"+name]; ok {
+			return v, true
+		}
+	}
+	v, ok := r.cfg.TemplateData[name]
+	return v, ok
+}
+
+// render writes the rendered nodes to sb, pound is the formatted number of the enclosing plural argument.
+func (r *icuRenderer) render(sb *strings.Builder, nodes []icuNode, pound string) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case icuText:
+			sb.WriteString(string(n))
+		case icuPound:
+			sb.WriteString(pound)
+		case icuArg:
+			if value, ok := r.value(n.name); ok {
+				sb.WriteString(formatIcuArg(value, n.argType, n.style, r.symbols))
+			} else {
+				sb.WriteString("{" + n.name + "}")
+			}
+		case *icuComplexArg:
+			value, ok := r.value(n.name)
+			if !ok && r.cfg != nil {
+				// see LocalizeConfig.PluralCount, LocalizeConfig.OrdinalCount and LocalizeConfig.Select
+				switch n.argType {
+				case "plural":
+					value, ok = r.cfg.PluralCount, r.cfg.PluralCount != nil
+				case "selectordinal":
+					value, ok = r.cfg.OrdinalCount, r.cfg.OrdinalCount != nil
+				case "select":
+					value, ok = r.cfg.Select, r.cfg.Select != ""
+				}
+			}
+			if n.argType == "select" {
+				option := n.option(pluralOther)
+				if ok {
+					if selected := n.option(fmt.Sprint(value)); selected != nil {
+						option = selected
+					}
+				}
+				r.render(sb, option, pound)
+				continue
+			}
+			option, number := r.pluralOption(n, value, ok)
+			r.render(sb, option, number)
+		}
+	}
+}
+
+// pluralOption returns the selected option of a plural/selectordinal argument and the formatted number for "#".
+// Explicit value options (e.g. "=0") are matched against value, keyword options against the plural category of value
+// minus the argument's offset; if there is no match, option "other" is selected.
+func (r *icuRenderer) pluralOption(arg *icuComplexArg, value interface{}, ok bool) ([]icuNode, string) {
+	number, isNumber := icuNumber(value)
+	if !ok || !isNumber {
+		return arg.option(pluralOther), ""
+	}
+	if arg.offset != 0 {
+		value = strconv.FormatFloat(number-float64(arg.offset), 'f', -1, 64)
+	}
+	pound := formatIcuNumber(value, "", r.symbols)
+	for _, opt := range arg.options {
+		if strings.HasPrefix(opt.key, "=") {
+			if exact, err := strconv.ParseFloat(opt.key[1:], 64); err == nil && exact == number {
+				return opt.nodes, pound
+			}
+		}
+	}
+	category := pluralOther
+	if r.plural != nil {
+		rules := r.plural.cardinal
+		if arg.argType == "selectordinal" {
+			rules = r.plural.ordinal
+		}
+		if operands, ok := newPluralOperands(value); ok {
+			category = rules.category(operands)
+		}
+	}
+	if option := arg.option(category); option != nil {
+		return option, pound
+	}
+	return arg.option(pluralOther), pound
+}
+
+// icuNumber converts a value to a number, returns false if value is not a number.
+func icuNumber(value interface{}) (float64, bool) {
+	if s, ok := value.(string); ok {
+		number, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+		return number, err == nil
+	}
+	if _, ok := newPluralOperands(value); !ok {
+		return 0, false
+	}
+	number, err := reddo.ToFloat(value)
+	return number, err == nil
+}
+
+// icuNumberSymbols are the decimal and grouping separators of numbers of a locale, and the minimum number of digits of
+// the integer part above 3 for the grouping separator to be used (e.g. 2 for "es": "1234" but "12.345").
+type icuNumberSymbols struct {
+	decimal, group string
+	minGrouping    int
+}
+
+// icuNumberSymbolsTable holds number symbols of CLDR by (lower-cased) locale, locales not listed use those of "en".
+var icuNumberSymbolsTable = map[string]icuNumberSymbols{
+	"en": {".", ",", 1}, "bg": {",", "\u00a0", 2}, "cs": {",", "\u00a0", 1}, "da": {",", ".", 1},
+	"de": {",", ".", 1}, "de-ch": {".", "’", 1}, "el": {",", ".", 1}, "es": {",", ".", 2},
+	"fi": {",", "\u00a0", 1}, "fr": {",", "\u202f", 1}, "hr": {",", ".", 1}, "hu": {",", "\u00a0", 1},
+	"id": {",", ".", 1}, "it": {",", ".", 1}, "nb": {",", "\u00a0", 1}, "nl": {",", ".", 1},
+	"pl": {",", "\u00a0", 2}, "pt": {",", ".", 1}, "pt-pt": {",", "\u00a0", 2}, "ro": {",", ".", 1},
+	"ru": {",", "\u00a0", 1}, "sk": {",", "\u00a0", 1}, "sl": {",", ".", 1}, "sr": {",", ".", 1},
+	"sv": {",", "\u00a0", 1}, "tr": {",", ".", 1}, "uk": {",", "\u00a0", 1}, "vi": {",", ".", 1},
+}
+
+// lookupIcuNumberSymbols finds the number symbols of a locale, subtags are removed from the end of the locale until a
+// match is found (see function lookupPluralRules).
+func lookupIcuNumberSymbols(locale string) icuNumberSymbols {
+	for key := normalizePluralLocale(locale); key != ""; {
+		if symbols, ok := icuNumberSymbolsTable[key]; ok {
+			return symbols
+		}
+		pos := strings.LastIndex(key, "-")
+		if pos < 0 {
+			break
+		}
+		key = key[:pos]
+	}
+	return icuNumberSymbolsTable["en"]
+}
+
+var reIcuDecimal = regexp.MustCompile(`^[-+]?\d+(\.\d+)?$`)
+
+// format replaces the decimal point of a decimal number (e.g. "-1234.5") with the decimal separator, and groups digits
+// of the integer part by thousands with the grouping separator.
+func (s icuNumberSymbols) format(decimal string) string {
+	sign, integer, fraction := "", decimal, ""
+	if strings.HasPrefix(integer, "-") || strings.HasPrefix(integer, "+") {
+		sign, integer = integer[:1], integer[1:]
+	}
+	if pos := strings.IndexByte(integer, '.'); pos >= 0 {
+		integer, fraction = integer[:pos], s.decimal+integer[pos+1:]
+	}
+	if len(integer) >= 3+s.minGrouping {
+		var sb strings.Builder
+		for i, c := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				sb.WriteString(s.group)
+			}
+			sb.WriteRune(c)
+		}
+		integer = sb.String()
+	}
+	return sign + integer + fraction
+}
+
+// formatIcuNumber formats a number argument with the decimal and grouping separators of a locale. Style "integer"
+// rounds the number, style "percent" multiplies it by 100 and rounds it; otherwise the number is rounded to at most 3
+// fraction digits, numbers passed as strings (e.g. "1.50") are formatted with their digits as is.
+func formatIcuNumber(value interface{}, style string, symbols icuNumberSymbols) string {
+	number, ok := icuNumber(value)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	switch style {
+	case "integer":
+		return symbols.format(strconv.FormatFloat(math.Round(number), 'f', -1, 64))
+	case "percent":
+		return symbols.format(strconv.FormatFloat(math.Round(number*100), 'f', -1, 64)) + "%"
+	}
+	if s, ok := value.(string); ok {
+		if s = strings.ReplaceAll(strings.TrimSpace(s), ",", ""); reIcuDecimal.MatchString(s) {
+			return symbols.format(s)
+		}
+	}
+	return symbols.format(strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(number, 'f', 3, 64), "0"), "."))
+}
+
+// icuDateLayouts and icuTimeLayouts are the layouts of date and time arguments by style ("medium" if not specified).
+var (
+	icuDateLayouts = map[string]string{"short": "1/2/06", "medium": "Jan 2, 2006", "long": "January 2, 2006", "full": "Monday, January 2, 2006"}
+	icuTimeLayouts = map[string]string{"short": "3:04 PM", "medium": "3:04:05 PM", "long": "3:04:05 PM MST", "full": "3:04:05 PM MST"}
+)
+
+// formatIcuArg formats the value of a simple argument, e.g. "{n, number}", "{d, date, short}" or "{t, time}". Numbers
+// are formatted with the number symbols of the locale (see function formatIcuNumber). Values of date and time arguments
+// must be time.Time; dates and times are formatted in English.
+func formatIcuArg(value interface{}, argType, style string, symbols icuNumberSymbols) string {
+	switch argType {
+	case "number":
+		return formatIcuNumber(value, style, symbols)
+	case "date", "time":
+		layouts := icuDateLayouts
+		if argType == "time" {
+			layouts = icuTimeLayouts
+		}
+		layout, ok := layouts[style]
+		if !ok {
+			layout = layouts["medium"]
+		}
+		switch t := value.(type) {
+		case time.Time:
+			return t.Format(layout)
+		case *time.Time:
+			if t != nil {
+				return t.Format(layout)
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// renderIcu renders the message in ICU MessageFormat syntax (see Message.Syntax).
+func (m *Message) renderIcu(plural *pluralSelector, cfg *LocalizeConfig) string {
+	compiled := m.templates()
+	if compiled.icuErr != nil {
+		log.Printf("[WARN] error parsing message [%s]: %s", m.Id, compiled.icuErr)
+		return m.Other
+	}
+	var sb strings.Builder
+	renderer := &icuRenderer{plural: plural, cfg: cfg, symbols: icuNumberSymbolsTable["en"]}
+	if plural != nil {
+		renderer.symbols = lookupIcuNumberSymbols(plural.locale)
+	}
+	renderer.render(&sb, compiled.icu, "")
+	return sb.String()
+}
@@ -0,0 +1,149 @@
+package goyai
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseIcuMessage(t *testing.T) {
+	testName := "TestParseIcuMessage"
+	testCases := []struct {
+		pattern  string
+		expected []icuNode
+	}{
+		{"Hello {name}!", []icuNode{icuText("Hello "), icuArg{name: "name"}, icuText("!")}},
+		{"It's '{'literal'}' and ''quoted''", []icuNode{icuText("It's {literal} and 'quoted'")}},
+		{"{price, number, integer} {d, date, short}", []icuNode{icuArg{name: "price", argType: "number", style: "integer"}, icuText(" "), icuArg{name: "d", argType: "date", style: "short"}}},
+		{"{n, plural, offset:1 =0{none} one{# item} other{'#' {n}}}", []icuNode{&icuComplexArg{name: "n", argType: "plural", offset: 1, options: []icuOption{
+			{key: "=0", nodes: []icuNode{icuText("none")}},
+			{key: "one", nodes: []icuNode{icuPound{}, icuText(" item")}},
+			{key: "other", nodes: []icuNode{icuText("# "), icuArg{name: "n"}}},
+		}}}},
+		{"{g, select, male{He #} other{They}}", []icuNode{&icuComplexArg{name: "g", argType: "select", options: []icuOption{
+			{key: "male", nodes: []icuNode{icuText("He #")}},
+			{key: "other", nodes: []icuNode{icuText("They")}},
+		}}}},
+	}
+	for _, tc := range testCases {
+		nodes, err := parseIcuMessage(tc.pattern)
+		if err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.pattern, err)
+		}
+		if !reflect.DeepEqual(nodes, tc.expected) {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.pattern, tc.expected, nodes)
+		}
+	}
+	for _, invalid := range []string{"Hello {name", "Hello }", "{n, plural, one{# item}}", "{n, plural, one # item}", "{}",
+		"{price, number, currency}", "{n, number, ::compact-short}", "{d, date, yyyy}", "{n, spellout}"} {
+		if _, err := parseIcuMessage(invalid); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
+
+func TestTemplateToIcu(t *testing.T) {
+	testName := "TestTemplateToIcu"
+	testCases := []struct {
+		tpl      string
+		inPlural bool
+		expected string
+	}{
+		{"Hello {{.name}}, {{._1}}", false, "Hello {name}, {1}"},
+		{"It's {x} #1", false, "It''s '{'x'}' #1"},
+		{"{{.n}} files #1", true, "{n} files '#'1"},
+	}
+	for _, tc := range testCases {
+		if v := templateToIcu(tc.tpl, tc.inPlural); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.tpl, tc.expected, v)
+		}
+	}
+}
+
+func TestMessage_renderIcu(t *testing.T) {
+	testName := "TestMessage_renderIcu"
+	date := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+	testCases := []struct {
+		locale, pattern string
+		cfg             *LocalizeConfig
+		expected        string
+	}{
+		{"en", "Hello {name}!", &LocalizeConfig{TemplateData: map[string]interface{}{"name": "Thanh"}}, "Hello Thanh!"},
+		{"en", "Hello {name}!", nil, "Hello {name}!"},
+		{"en", "{0} and {1}", &LocalizeConfig{TemplateData: map[string]interface{}{"_0": "A", "_1": "B"}}, "A and B"},
+		{"en", "{n, plural, =0 {No files} one {# file} other {# files}}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 0}}, "No files"},
+		{"en", "{n, plural, =0 {No files} one {# file} other {# files}}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 1}}, "1 file"},
+		{"en", "{n, plural, =0 {No files} one {# file} other {# files}}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": "1.0"}}, "1.0 files"},
+		{"en", "{n, plural, =0 {No files} one {# file} other {# files}}", &LocalizeConfig{PluralCount: 42}, "42 files"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", &LocalizeConfig{PluralCount: 22}, "22 файла"},
+		{"en", "{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # guest} other {{host} and # guests}}",
+			&LocalizeConfig{TemplateData: map[string]interface{}{"n": 2, "host": "Ann"}}, "Ann and 1 guest"},
+		{"en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", &LocalizeConfig{OrdinalCount: 23}, "23rd"},
+		{"en", "{gender, select, female {{name} replied to her post} male {{name} replied to his post} other {{name} replied to their post}}",
+			&LocalizeConfig{TemplateData: map[string]interface{}{"gender": "female", "name": "Ann"}}, "Ann replied to her post"},
+		{"en", "{gender, select, female {her} other {their}}", &LocalizeConfig{TemplateData: map[string]interface{}{"gender": "robot"}}, "their"},
+		{"en", "{n, plural, one {{g, select, female {She has # cat} other {They have # cat}}} other {{g, select, female {She has # cats} other {They have # cats}}}}",
+			&LocalizeConfig{PluralCount: 3, TemplateData: map[string]interface{}{"g": "female"}}, "She has 3 cats"},
+		{"en", "{n, number} / {n, number, integer} / {r, number, percent}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 1234.5, "r": 0.256}}, "1,234.5 / 1,235 / 26%"},
+		{"de", "{n, number} / {n, number, integer} / {m, number}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 1234567.891, "m": "-1,000.50"}}, "1.234.567,891 / 1.234.568 / -1.000,50"},
+		{"fr-CA", "{n, number}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 12345.6789}}, "12\u202f345,679"},
+		{"es", "{n, number} / {m, number}", &LocalizeConfig{TemplateData: map[string]interface{}{"n": 1234, "m": 12345}}, "1234 / 12.345"},
+		{"vi", "{n, plural, other {# tập tin}}", &LocalizeConfig{PluralCount: 1000}, "1.000 tập tin"},
+		{"en", "{d, date} / {d, date, short} / {d, date, full} / {d, time, short}", &LocalizeConfig{TemplateData: map[string]interface{}{"d": date}},
+			"Mar 5, 2024 / 3/5/24 / Tuesday, March 5, 2024 / 2:07 PM"},
+		{"en", "It''s '{'escaped'}'", nil, "It's {escaped}"},
+	}
+	for _, tc := range testCases {
+		msg := &Message{Id: "mid", Other: tc.pattern, Syntax: SyntaxIcu}
+		if v := msg.render(newPluralSelector(tc.locale, false), tc.cfg); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.pattern, tc.expected, v)
+		}
+	}
+}
+
+func TestBuildI18n_IcuSyntax(t *testing.T) {
+	testName := "TestBuildI18n_IcuSyntax"
+	content := `
+en:
+  _syntax: icu
+  inbox: "{count, plural, =0 {No new mail} one {# new message} other {# new messages}}"
+  greeting: "Hello {name}, you have {count, number} points"
+  template:
+    syntax: template
+    other: "Hello {{.name}}"
+vi:
+  inbox:
+    syntax: icu
+    other: "{count, plural, =0 {Không có thư mới} other {# thư mới}}"
+  template: "Xin chào {{.name}}"
+`
+	i18n, err := BuildI18nFromBytes([]byte(content), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	testCases := []struct {
+		locale, msgId string
+		params        []interface{}
+		expected      string
+	}{
+		{"en", "inbox", []interface{}{LocalizeConfig{PluralCount: 0}}, "No new mail"},
+		{"en", "inbox", []interface{}{LocalizeConfig{PluralCount: 1}}, "1 new message"},
+		{"en", "inbox", []interface{}{LocalizeConfig{TemplateData: map[string]interface{}{"count": 5}}}, "5 new messages"},
+		{"en", "greeting", []interface{}{"Thanh", 10}, "Hello Thanh, you have 10 points"},
+		{"en", "template", []interface{}{"Thanh"}, "Hello Thanh"},
+		{"vi", "inbox", []interface{}{LocalizeConfig{PluralCount: 0}}, "Không có thư mới"},
+		{"vi", "inbox", []interface{}{LocalizeConfig{PluralCount: 3}}, "3 thư mới"},
+		{"vi", "template", []interface{}{"Thanh"}, "Xin chào Thanh"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize(tc.locale, tc.msgId, tc.params...); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	for _, invalid := range []string{"en:\n  _syntax: icu\n  broken: \"{count, plural, one {# item}}\"", "en:\n  msg:\n    syntax: unknown\n    other: Hi"} {
+		if _, err := BuildI18nFromBytes([]byte(invalid), I18nOptions{DefaultLocale: "en"}); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
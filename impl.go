@@ -4,16 +4,37 @@ import (
 	"log"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 )
 
 // Goi18n is the default I18n implementation from goyai.
 type Goi18n struct {
-	defaultLocale string
-	locales       map[string]*LocaleInfo
-	cachedLocales []LocaleInfo
-	messagesStore map[string]map[string]*Message // {locale->{msg-id->msg-data}}
-	lock          sync.Mutex
+	defaultLocale   string
+	locales         map[string]*LocaleInfo
+	cachedLocales   []LocaleInfo
+	messagesStore   map[string]map[string]*Message // {locale->{msg-id->msg-data}}
+	legacyPlural    bool
+	fallbackLocales map[string][]string
+	lock            sync.Mutex
+}
+
+func newGoi18n(opts I18nOptions, localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message) *Goi18n {
+	fallbackLocales := make(map[string][]string, len(opts.FallbackLocales))
+	for locale, fallbacks := range opts.FallbackLocales {
+		locale = canonicalLocale(locale)
+		for _, fallback := range fallbacks {
+			fallbackLocales[locale] = append(fallbackLocales[locale], canonicalLocale(fallback))
+		}
+	}
+	return &Goi18n{
+		defaultLocale:   canonicalLocale(opts.DefaultLocale),
+		locales:         localesStore,
+		messagesStore:   messagesStore,
+		legacyPlural:    opts.LegacyPluralRules,
+		fallbackLocales: fallbackLocales,
+	}
 }
 
 // Localise implements I18n.Localise
@@ -35,21 +56,50 @@ func _extractFirstConfig(params ...interface{}) *LocalizeConfig {
 
 var rePlaceholderToken = regexp.MustCompile(`{{\$?\.([\w]+).*?}}`)
 
-func _buildTemplateData(msg string, params ...interface{}) map[string]interface{} {
-	templateData := make(map[string]interface{})
-	forwardMap := make(map[string]int)
-	reverseMap := make(map[int]string)
+// templateLiteralBrace is the template action that outputs a literal "{", used by loaders to escape "{" of messages
+// converted from other formats.
+const templateLiteralBrace = `{{"{"}}`
+
+// escapeTemplateBraces escapes all "{" of a text as templateLiteralBrace, so that the text is rendered as-is by a
+// message template (e.g. "{{x}}" of an Android string resource is not a template action).
+func escapeTemplateBraces(s string) string {
+	return strings.ReplaceAll(s, "{", templateLiteralBrace)
+}
+
+// placeholderPositions maps placeholder tokens of a message template to positions of the params passed to Localize:
+// positional placeholders (e.g. {{._1}}) are mapped to params at the same position, named placeholders are mapped to
+// the remaining positions in order of first appearance.
+func placeholderPositions(msg string) (forwardMap map[string]int, reverseMap map[int]string) {
+	forwardMap = make(map[string]int)
+	reverseMap = make(map[int]string)
 	matches := rePlaceholderToken.FindAllStringSubmatch(msg, -1)
+	for _, match := range matches {
+		// positional placeholders (e.g. {{._1}}) are mapped to params at the same position
+		token := match[1]
+		if pos, err := strconv.Atoi(strings.TrimPrefix(token, "_")); err == nil && strings.HasPrefix(token, "_") {
+			forwardMap[token] = pos
+			reverseMap[pos] = token
+		}
+	}
 	index := 0
 	for _, match := range matches {
 		token := match[1]
 		if _, ok := forwardMap[token]; !ok {
+			for reverseMap[index] != "" {
+				index++
+			}
 			forwardMap[token] = index
 			reverseMap[index] = token
 			index++
 		}
 	}
-	index = 0
+	return forwardMap, reverseMap
+}
+
+func _buildTemplateData(msg string, params ...interface{}) map[string]interface{} {
+	templateData := make(map[string]interface{})
+	_, reverseMap := placeholderPositions(msg)
+	index := 0
 	for _, param := range params {
 		switch v := param.(type) {
 		case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
@@ -64,17 +114,44 @@ func _buildTemplateData(msg string, params ...interface{}) map[string]interface{
 
 // Localize implements I18n.Localize
 func (i *Goi18n) Localize(locale, msgId string, params ...interface{}) string {
+	return i.localize([]string{locale}, msgId, nil, params...)
+}
+
+// localizeCount overrides the plural count of a LocalizeConfig, used by Localizer.Plural and Localizer.Ordinal.
+type localizeCount struct {
+	count   interface{}
+	ordinal bool
+}
+
+// localize returns a localized message, looked up in the locale chains of locales (see function localeChain).
+func (i *Goi18n) localize(locales []string, msgId string, count *localizeCount, params ...interface{}) string {
 	cfg := _extractFirstConfig(params...)
 	var msg string
-	localizedMessage := i.getLocalizedMessage(msgId, locale, i.defaultLocale)
+	localizedMessage, msgLocale := i.getLocalizedMessage(msgId, locales, i.defaultLocale)
 	if localizedMessage != nil {
-		if cfg == nil && len(params) > 0 {
-			cfg = &LocalizeConfig{TemplateData: _buildTemplateData(localizedMessage.Other, params...)}
+		positional := cfg == nil && len(params) > 0
+		if count != nil {
+			cfgWithCount := LocalizeConfig{}
+			if cfg != nil {
+				cfgWithCount = *cfg
+			}
+			if count.ordinal {
+				cfgWithCount.OrdinalCount = count.count
+			} else {
+				cfgWithCount.PluralCount = count.count
+			}
+			cfg = &cfgWithCount
 		}
-		msg = localizedMessage.render(cfg)
+		if positional {
+			if cfg == nil {
+				cfg = &LocalizeConfig{}
+			}
+			cfg.TemplateData = localizedMessage.positionalTemplateData(cfg, params...)
+		}
+		msg = localizedMessage.render(newPluralSelector(msgLocale, i.legacyPlural), cfg)
 	}
 	if msg == "" {
-		log.Printf("[WARN] localized message [%s] not defined for locale [%s]", msgId, locale)
+		log.Printf("[WARN] localized message [%s] not defined for locale [%s]", msgId, strings.Join(locales, ","))
 	}
 	if msg == "" && cfg != nil {
 		msg = cfg.DefaultMessage
@@ -82,21 +159,52 @@ func (i *Goi18n) Localize(locale, msgId string, params ...interface{}) string {
 	return msg
 }
 
-func (i *Goi18n) getLocalizedMessage(msgId, locale, defaultLocale string) *Message {
+// getLocalizedMessage returns the localized message and the locale it belongs to.
+//
+// The message is looked up in the locale chain of locales (see function localeChain), the first found one is returned.
+func (i *Goi18n) getLocalizedMessage(msgId string, locales []string, defaultLocale string) (*Message, string) {
 	if i.messagesStore == nil {
-		return nil
+		return nil, ""
 	}
-	if locale == "" || i.locales[locale] == nil {
-		if locale != "" {
-			log.Printf("[WARN] locale [%s] not exist, revert back to default", locale)
+	for _, l := range i.localeChain(locales, defaultLocale) {
+		if msg := i.messagesStore[l][msgId]; msg != nil {
+			return msg, l
 		}
-		locale = defaultLocale
 	}
-	localizedMessagesData := i.messagesStore[locale]
-	if localizedMessagesData != nil {
-		return localizedMessagesData[msgId]
+	return nil, ""
+}
+
+// localeChain returns the available locales to look up messages for a list of locales, in order: for each locale,
+// the locale itself, its fallback locales (see I18nOptions.FallbackLocales) and its parent locales (e.g. "pt" is parent
+// of "pt-BR"); and finally the default locale.
+func (i *Goi18n) localeChain(locales []string, defaultLocale string) []string {
+	chain := make([]string, 0, 4)
+	visited := make(map[string]bool)
+	var visit func(string)
+	visit = func(l string) {
+		for ; l != "" && !visited[l]; l = parentLocale(l) {
+			visited[l] = true
+			if i.locales[l] != nil {
+				chain = append(chain, l)
+			}
+			for _, fallback := range i.fallbackLocales[l] {
+				visit(fallback)
+			}
+		}
 	}
-	return nil
+	for _, locale := range locales {
+		visit(canonicalLocale(locale))
+	}
+	if len(chain) == 0 && strings.Join(locales, "") != "" {
+		log.Printf("[WARN] locale [%s] not exist, revert back to default", strings.Join(locales, ","))
+	}
+	visit(defaultLocale)
+	return chain
+}
+
+// Localizer returns a Localizer bound to the locales, see function NewLocalizer.
+func (i *Goi18n) Localizer(locales ...string) *Localizer {
+	return NewLocalizer(i, locales...)
 }
 
 // AvailableLocales implements I18n.AvailableLocales.
@@ -0,0 +1,282 @@
+package goyai
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidLocale indicates that the locale is not a well-formed BCP 47 language tag.
+	//
+	// Available since v0.3.0
+	ErrInvalidLocale = errors.New("locale is not a well-formed BCP 47 language tag")
+)
+
+// LocaleTag is a parsed BCP 47 language tag (e.g. "zh-Hant-TW", "de-CH-1996", "th-TH-u-nu-thai").
+//
+// Available since v0.3.0
+type LocaleTag struct {
+	// Language is the primary language subtag, in lower case (e.g. "en").
+	Language string
+
+	// Script is the script subtag, in title case (e.g. "Hant"). Empty if the tag has no script subtag.
+	Script string
+
+	// Region is the region subtag, in upper case (e.g. "US", "419"). Empty if the tag has no region subtag.
+	Region string
+
+	// Variants are the variant subtags, in lower case (e.g. ["1996"]).
+	Variants []string
+
+	// Keywords are the Unicode locale extension ("-u-" extension) keywords, in lower case (e.g. {"nu": "thai"}).
+	// A keyword without value is mapped to an empty string.
+	Keywords map[string]string
+
+	// Extensions are the other extensions and the private use subtags, in lower case (e.g. ["t-es-mx", "x-private"]).
+	Extensions []string
+}
+
+// languageAliases maps deprecated language subtags to their preferred values, as specified by the IANA
+// Language Subtag Registry.
+var languageAliases = map[string]string{"iw": "he", "in": "id", "ji": "yi", "jw": "jv", "mo": "ro"}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDigit(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphaNum(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ParseLocaleTag parses a BCP 47 language tag. Both "-" and "_" are accepted as subtag separators, and subtags are
+// case-insensitive (e.g. "en-US", "en_US" and "EN-us" are the same tag).
+//
+// Available since v0.3.0
+func ParseLocaleTag(locale string) (LocaleTag, error) {
+	subtags := strings.FieldsFunc(strings.ToLower(strings.TrimSpace(locale)), func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) == 0 || len(strings.TrimSpace(locale)) != len(strings.Join(subtags, "-")) {
+		return LocaleTag{}, ErrInvalidLocale
+	}
+	tag := LocaleTag{}
+	pos := 0
+
+	// language: 2-3 letters optionally followed by up to 3 extended language subtags, or 4-8 letters
+	if lang := subtags[pos]; isAlpha(lang) && len(lang) >= 2 && len(lang) <= 8 {
+		tag.Language = lang
+		pos++
+		for i := 0; i < 3 && len(lang) <= 3 && pos < len(subtags) && len(subtags[pos]) == 3 && isAlpha(subtags[pos]); i++ {
+			// extended language subtag is the preferred primary language (e.g. "zh-yue" is canonicalized to "yue")
+			tag.Language = subtags[pos]
+			pos++
+		}
+		if alias, ok := languageAliases[tag.Language]; ok {
+			tag.Language = alias
+		}
+	} else {
+		return LocaleTag{}, ErrInvalidLocale
+	}
+
+	// script: 4 letters
+	if pos < len(subtags) && len(subtags[pos]) == 4 && isAlpha(subtags[pos]) {
+		tag.Script = strings.ToUpper(subtags[pos][:1]) + subtags[pos][1:]
+		pos++
+	}
+
+	// region: 2 letters or 3 digits
+	if pos < len(subtags) && (len(subtags[pos]) == 2 && isAlpha(subtags[pos]) || len(subtags[pos]) == 3 && isDigit(subtags[pos])) {
+		tag.Region = strings.ToUpper(subtags[pos])
+		pos++
+	}
+
+	// variants: 5-8 alphanumerics, or 4 alphanumerics starting with a digit
+	for pos < len(subtags) {
+		variant := subtags[pos]
+		if !isAlphaNum(variant) || !(len(variant) >= 5 && len(variant) <= 8 || len(variant) == 4 && isDigit(variant[:1])) {
+			break
+		}
+		tag.Variants = append(tag.Variants, variant)
+		pos++
+	}
+
+	// extensions: a singleton followed by one or more 2-8 alphanumerics; private use: "x" followed by one or more 1-8 alphanumerics
+	for pos < len(subtags) {
+		singleton := subtags[pos]
+		if len(singleton) != 1 || !isAlphaNum(singleton) {
+			return LocaleTag{}, ErrInvalidLocale
+		}
+		pos++
+		start := pos
+		if singleton == "x" {
+			// private use subtags extend to the end of the tag
+			for ; pos < len(subtags); pos++ {
+				if !isAlphaNum(subtags[pos]) || len(subtags[pos]) > 8 {
+					return LocaleTag{}, ErrInvalidLocale
+				}
+			}
+		} else {
+			for pos < len(subtags) && isAlphaNum(subtags[pos]) && len(subtags[pos]) >= 2 && len(subtags[pos]) <= 8 {
+				pos++
+			}
+		}
+		if pos == start {
+			return LocaleTag{}, ErrInvalidLocale
+		}
+		if singleton == "u" {
+			tag.parseUnicodeExtension(subtags[start:pos])
+		} else {
+			tag.Extensions = append(tag.Extensions, singleton+"-"+strings.Join(subtags[start:pos], "-"))
+		}
+	}
+	sort.SliceStable(tag.Extensions, func(i, j int) bool {
+		// private use subtags are always at the end
+		if tag.Extensions[i][0] == 'x' || tag.Extensions[j][0] == 'x' {
+			return tag.Extensions[j][0] == 'x' && tag.Extensions[i][0] != 'x'
+		}
+		return tag.Extensions[i][0] < tag.Extensions[j][0]
+	})
+	return tag, nil
+}
+
+// parseUnicodeExtension parses subtags of a "-u-" extension into keywords. Attributes (subtags preceding the first
+// keyword) are stored as keywords without value.
+func (t *LocaleTag) parseUnicodeExtension(subtags []string) {
+	if t.Keywords == nil {
+		t.Keywords = make(map[string]string)
+	}
+	key := ""
+	for _, subtag := range subtags {
+		if len(subtag) == 2 {
+			key = subtag
+			t.Keywords[key] = ""
+		} else if key == "" {
+			t.Keywords[subtag] = ""
+		} else if t.Keywords[key] == "" {
+			t.Keywords[key] = subtag
+		} else {
+			t.Keywords[key] += "-" + subtag
+		}
+	}
+}
+
+// String returns the canonical form of the tag (e.g. "zh-Hant-TW", "th-TH-u-nu-thai").
+func (t LocaleTag) String() string {
+	if t.Language == "" {
+		return ""
+	}
+	subtags := []string{t.Language}
+	if t.Script != "" {
+		subtags = append(subtags, t.Script)
+	}
+	if t.Region != "" {
+		subtags = append(subtags, t.Region)
+	}
+	subtags = append(subtags, t.Variants...)
+	privateUse := ""
+	unicodeExtDone := len(t.Keywords) == 0
+	for _, ext := range t.Extensions {
+		if ext[0] == 'x' {
+			privateUse = ext
+			continue
+		}
+		if !unicodeExtDone && ext[0] > 'u' {
+			subtags = append(subtags, t.unicodeExtension())
+			unicodeExtDone = true
+		}
+		subtags = append(subtags, ext)
+	}
+	if !unicodeExtDone {
+		subtags = append(subtags, t.unicodeExtension())
+	}
+	if privateUse != "" {
+		subtags = append(subtags, privateUse)
+	}
+	return strings.Join(subtags, "-")
+}
+
+func (t LocaleTag) unicodeExtension() string {
+	// attributes come first, then keywords sorted by key
+	var attrs, keys []string
+	for k := range t.Keywords {
+		if len(k) == 2 {
+			keys = append(keys, k)
+		} else {
+			attrs = append(attrs, k)
+		}
+	}
+	sort.Strings(attrs)
+	sort.Strings(keys)
+	subtags := append([]string{"u"}, attrs...)
+	for _, k := range keys {
+		subtags = append(subtags, k)
+		if v := t.Keywords[k]; v != "" && v != "true" {
+			subtags = append(subtags, v)
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
+// Parent returns the parent of the tag, which is derived by removing extensions, or the last variant, region or script
+// subtag (e.g. "zh-Hant-TW" → "zh-Hant" → "zh"). It returns false if the tag has no parent.
+func (t LocaleTag) Parent() (LocaleTag, bool) {
+	parent := LocaleTag{Language: t.Language, Script: t.Script, Region: t.Region, Variants: t.Variants}
+	switch {
+	case len(t.Keywords) > 0 || len(t.Extensions) > 0:
+	case len(t.Variants) > 0:
+		parent.Variants = t.Variants[:len(t.Variants)-1]
+		if len(parent.Variants) == 0 {
+			parent.Variants = nil
+		}
+	case t.Region != "":
+		parent.Region = ""
+	case t.Script != "":
+		parent.Script = ""
+	default:
+		return LocaleTag{}, false
+	}
+	return parent, true
+}
+
+// canonicalLocale returns the canonical form of a locale if it is a well-formed BCP 47 language tag. Otherwise, the
+// locale is returned as-is.
+func canonicalLocale(locale string) string {
+	if tag, err := ParseLocaleTag(locale); err == nil {
+		return tag.String()
+	}
+	return locale
+}
+
+// parentLocale returns the parent of a locale (e.g. "pt-BR" → "pt", see LocaleTag.Parent). If the locale is not a
+// well-formed BCP 47 language tag, the parent is derived by removing the last subtag. It returns an empty string if
+// the locale has no parent.
+func parentLocale(locale string) string {
+	if tag, err := ParseLocaleTag(locale); err == nil {
+		if parent, ok := tag.Parent(); ok {
+			return parent.String()
+		}
+		return ""
+	}
+	if pos := strings.LastIndexAny(locale, "-_"); pos > 0 {
+		return locale[:pos]
+	}
+	return ""
+}
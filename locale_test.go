@@ -0,0 +1,86 @@
+package goyai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParentLocale(t *testing.T) {
+	testName := "TestParentLocale"
+	testCases := map[string]string{"pt-BR": "pt", "zh-Hant-TW": "zh-Hant", "zh-Hant": "zh", "en_US": "en", "en": "", "": "", "-en": "",
+		"en-US-u-ca-gregory": "en-US", "t1-x": "t1", "en2": ""}
+	for locale, expected := range testCases {
+		if v := parentLocale(locale); v != expected {
+			t.Fatalf("%s failed: locale [%s] / expected parent [%s] but received [%s]", testName, locale, expected, v)
+		}
+	}
+}
+
+func TestParseLocaleTag(t *testing.T) {
+	testName := "TestParseLocaleTag"
+	testCases := []struct {
+		input     string
+		expected  LocaleTag
+		canonical string
+	}{
+		{"en", LocaleTag{Language: "en"}, "en"},
+		{"EN-us", LocaleTag{Language: "en", Region: "US"}, "en-US"},
+		{"en_US", LocaleTag{Language: "en", Region: "US"}, "en-US"},
+		{"zh-hant-tw", LocaleTag{Language: "zh", Script: "Hant", Region: "TW"}, "zh-Hant-TW"},
+		{"es-419", LocaleTag{Language: "es", Region: "419"}, "es-419"},
+		{"de-CH-1996", LocaleTag{Language: "de", Region: "CH", Variants: []string{"1996"}}, "de-CH-1996"},
+		{"sl-rozaj-biske", LocaleTag{Language: "sl", Variants: []string{"rozaj", "biske"}}, "sl-rozaj-biske"},
+		{"th-TH-u-NU-thai-ca-buddhist", LocaleTag{Language: "th", Region: "TH", Keywords: map[string]string{"nu": "thai", "ca": "buddhist"}}, "th-TH-u-ca-buddhist-nu-thai"},
+		{"en-x-Private-u-ca", LocaleTag{Language: "en", Extensions: []string{"x-private-u-ca"}}, "en-x-private-u-ca"},
+		{"en-x-a-t-es", LocaleTag{Language: "en", Extensions: []string{"x-a-t-es"}}, "en-x-a-t-es"},
+		{"en-t-es-mx-a-bcd", LocaleTag{Language: "en", Extensions: []string{"a-bcd", "t-es-mx"}}, "en-a-bcd-t-es-mx"},
+		{"en-z-abc-u-ca-gregory", LocaleTag{Language: "en", Keywords: map[string]string{"ca": "gregory"}, Extensions: []string{"z-abc"}}, "en-u-ca-gregory-z-abc"},
+		{"iw-IL", LocaleTag{Language: "he", Region: "IL"}, "he-IL"},
+		{"zh-yue-HK", LocaleTag{Language: "yue", Region: "HK"}, "yue-HK"},
+		{"root", LocaleTag{Language: "root"}, "root"},
+	}
+	for _, testCase := range testCases {
+		tag, err := ParseLocaleTag(testCase.input)
+		if err != nil {
+			t.Fatalf("%s failed (%s): %s", testName, testCase.input, err)
+		}
+		if !reflect.DeepEqual(tag, testCase.expected) {
+			t.Fatalf("%s failed (%s), expected %#v but received %#v", testName, testCase.input, testCase.expected, tag)
+		}
+		if v := tag.String(); v != testCase.canonical {
+			t.Fatalf("%s failed (%s), expected canonical form [%s] but received [%s]", testName, testCase.input, testCase.canonical, v)
+		}
+	}
+}
+
+func TestParseLocaleTag_Invalid(t *testing.T) {
+	testName := "TestParseLocaleTag_Invalid"
+	for _, input := range []string{"", " ", "e", "en2", "t1", "123", "en--US", "-en", "en-", "en-US-u", "en-x", "en-abcdefghi", "en-US-u-ca-x", "en-a-b", "en-ä"} {
+		if tag, err := ParseLocaleTag(input); err == nil {
+			t.Fatalf("%s failed (%s), expected error but received %#v", testName, input, tag)
+		}
+	}
+}
+
+func TestLocaleTag_Parent(t *testing.T) {
+	testName := "TestLocaleTag_Parent"
+	chains := [][]string{
+		{"zh-Hant-TW", "zh-Hant", "zh"},
+		{"de-CH-1996-fonipa", "de-CH-1996", "de-CH", "de"},
+		{"en-US-u-ca-gregory-x-private", "en-US", "en"},
+		{"sr-Latn", "sr"},
+	}
+	for _, chain := range chains {
+		tag, _ := ParseLocaleTag(chain[0])
+		for _, expected := range chain[1:] {
+			parent, ok := tag.Parent()
+			if !ok || parent.String() != expected {
+				t.Fatalf("%s failed (%s), expected parent [%s] but received [%s]", testName, tag, expected, parent)
+			}
+			tag = parent
+		}
+		if parent, ok := tag.Parent(); ok {
+			t.Fatalf("%s failed (%s), expected no parent but received [%s]", testName, tag, parent)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package goyai
+
+import (
+	"context"
+)
+
+// Localizer is bound to one or more locales of an I18n instance and localizes messages for those locales, so that the
+// locale does not need to be passed around. Use NewLocalizer to obtain a Localizer.
+//
+// A Localizer can be passed to html/template templates, e.g. with the Localizer passed as a model named "i18n":
+//   - {{.i18n.Localize "hello_param" "Thanh"}}
+//   - {{.i18n.Plural "remaining_tasks" 3}}
+//   - {{.i18n.Ordinal "rank" 2 2}}
+//
+// or its functions can be registered via FuncMap, e.g. {{localize "hello_param" "Thanh"}}.
+//
+// Available since v0.3.0
+type Localizer struct {
+	i18n    I18n
+	locales []string
+}
+
+// NewLocalizer returns a Localizer bound to the locales of an I18n instance. Messages are looked up in the locales in
+// order, each with its fallback/parent locales, and finally in the default locale.
+//
+// Available since v0.3.0
+func NewLocalizer(i18n I18n, locales ...string) *Localizer {
+	return &Localizer{i18n: i18n, locales: locales}
+}
+
+// Locale returns the first locale the localizer is bound to.
+func (l *Localizer) Locale() string {
+	if len(l.locales) == 0 {
+		return ""
+	}
+	return l.locales[0]
+}
+
+// Locales returns all locales the localizer is bound to.
+func (l *Localizer) Locales() []string {
+	return append([]string{}, l.locales...)
+}
+
+func (l *Localizer) localize(msgId string, count *localizeCount, params ...interface{}) string {
+	if i18n, ok := l.i18n.(*Goi18n); ok {
+		return i18n.localize(l.locales, msgId, count, params...)
+	}
+	if count != nil {
+		// other I18n implementations do not support positional params together with a plural count
+		cfg := LocalizeConfig{}
+		if c := _extractFirstConfig(params...); c != nil {
+			cfg = *c
+		}
+		if count.ordinal {
+			cfg.OrdinalCount = count.count
+		} else {
+			cfg.PluralCount = count.count
+		}
+		params = []interface{}{cfg}
+	}
+	locales := l.locales
+	if len(locales) == 0 {
+		locales = []string{""}
+	}
+	var msg string
+	for _, locale := range locales {
+		if msg = l.i18n.Localize(locale, msgId, params...); msg != "" {
+			break
+		}
+	}
+	return msg
+}
+
+// Localize returns a localized message for the bound locales. See I18n.Localize for more information about params.
+func (l *Localizer) Localize(msgId string, params ...interface{}) string {
+	return l.localize(msgId, nil, params...)
+}
+
+// Localise is alias of Localize.
+func (l *Localizer) Localise(msgId string, params ...interface{}) string {
+	return l.Localize(msgId, params...)
+}
+
+// Plural returns a localized message with the plural form picked by count (see LocalizeConfig.PluralCount).
+// See I18n.Localize for more information about params.
+func (l *Localizer) Plural(msgId string, count interface{}, params ...interface{}) string {
+	return l.localize(msgId, &localizeCount{count: count}, params...)
+}
+
+// Ordinal returns a localized message with the ordinal form picked by count (see LocalizeConfig.OrdinalCount).
+// See I18n.Localize for more information about params.
+func (l *Localizer) Ordinal(msgId string, count interface{}, params ...interface{}) string {
+	return l.localize(msgId, &localizeCount{count: count, ordinal: true}, params...)
+}
+
+// FuncMap returns the localizer's functions to be registered to text/template or html/template templates via
+// Template.Funcs: "localize", "localise", "plural" and "ordinal".
+func (l *Localizer) FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"localize": l.Localize,
+		"localise": l.Localise,
+		"plural":   l.Plural,
+		"ordinal":  l.Ordinal,
+	}
+}
+
+type localizerContextKey struct{}
+
+// NewContext returns a new context that carries the localizer.
+//
+// Available since v0.3.0
+func NewContext(ctx context.Context, localizer *Localizer) context.Context {
+	return context.WithValue(ctx, localizerContextKey{}, localizer)
+}
+
+// FromContext returns the localizer carried by the context (e.g. stored by HttpMiddleware).
+//
+// If the context does not carry any localizer, a localizer bound to NullI18n is returned, hence the result is always
+// safe to use.
+//
+// Available since v0.3.0
+func FromContext(ctx context.Context) *Localizer {
+	if localizer, ok := ctx.Value(localizerContextKey{}).(*Localizer); ok && localizer != nil {
+		return localizer
+	}
+	return NewLocalizer(NullI18n())
+}
@@ -0,0 +1,154 @@
+package goyai
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFromContext_NoLocalizer(t *testing.T) {
+	testName := "TestFromContext_NoLocalizer"
+	localizer := FromContext(context.Background())
+	if localizer == nil {
+		t.Fatalf("%s failed: nil", testName)
+	}
+	if e, v := "", localizer.Localize("hello"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	testName := "TestFromContext"
+	i18n := _buildI18nFallback(t, testName)
+	ctx := NewContext(context.Background(), NewLocalizer(i18n, "pt-BR"))
+	localizer := FromContext(ctx)
+	if e, v := "pt-BR", localizer.Locale(); v != e {
+		t.Fatalf("%s failed, expected locale [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "Oi", localizer.Localize("hello"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+const yamlContentLocalizer = `---
+en:
+  hello: "Hello {{.name}}"
+  items:
+    one: "{{.n}} item"
+    other: "{{.n}} items"
+  rank:
+    one: "{{.n}}st"
+    two: "{{.n}}nd"
+    few: "{{.n}}rd"
+    other: "{{.n}}th"
+  only_en: "English only"
+vi:
+  hello: "Xin chào {{.name}}"
+  items: "{{.n}} món"
+fr:
+  only_fr: "Français seulement"
+`
+
+func _buildI18nLocalizer(t *testing.T, testName string) I18n {
+	os.RemoveAll(tempDir)
+	os.Mkdir(tempDir, 0711)
+	if err := ioutil.WriteFile(tempDir+yamlFile, []byte(yamlContentLocalizer), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	i18n, err := BuildI18n(I18nOptions{ConfigFileOrDir: tempDir + yamlFile, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	return i18n
+}
+
+func TestLocalizer_Locales(t *testing.T) {
+	testName := "TestLocalizer_Locales"
+	i18n := _buildI18nLocalizer(t, testName)
+	localizer := NewLocalizer(i18n, "vi", "fr")
+	if e, v := "vi", localizer.Locale(); v != e {
+		t.Fatalf("%s failed, expected locale [%s] but received [%s]", testName, e, v)
+	}
+	testCases := map[string]string{"hello": "Xin chào Thanh", "only_fr": "Français seulement", "only_en": "English only", "not_found": ""}
+	for msgId, expected := range testCases {
+		if v := localizer.Localize(msgId, "Thanh"); v != expected {
+			t.Fatalf("%s failed (%s), expected [%s] but received [%s]", testName, msgId, expected, v)
+		}
+	}
+	if e, v := "", NewLocalizer(i18n).Locale(); v != e {
+		t.Fatalf("%s failed, expected locale [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "Hello Thanh", NewLocalizer(i18n).Localise("hello", "Thanh"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestLocalizer_Plural(t *testing.T) {
+	testName := "TestLocalizer_Plural"
+	i18n := _buildI18nLocalizer(t, testName)
+	en, vi := NewLocalizer(i18n, "en"), NewLocalizer(i18n, "vi")
+	if e, v := "1 item", en.Plural("items", 1, 1); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "2 items", en.Plural("items", 2, LocalizeConfig{TemplateData: map[string]interface{}{"n": 2}, PluralCount: 1}); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "1 món", vi.Plural("items", 1, 1); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "22nd", vi.Ordinal("rank", 22, 22); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestLocalizer_HtmlTemplate(t *testing.T) {
+	testName := "TestLocalizer_HtmlTemplate"
+	i18n := _buildI18nLocalizer(t, testName)
+	localizer := NewLocalizer(i18n, "en")
+	tpl := htmltemplate.Must(htmltemplate.New("test").Funcs(localizer.FuncMap()).Parse(
+		`{{.i18n.Localize "hello" "<b>"}}|{{.i18n.Plural "items" 3 3}}|{{.i18n.Ordinal "rank" 3 3}}|{{localize "hello" "Thanh"}}|{{plural "items" 1 1}}|{{ordinal "rank" 11 11}}|{{localise "only_en"}}`))
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, map[string]interface{}{"i18n": localizer}); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Hello &lt;b&gt;|3 items|3rd|Hello Thanh|1 item|11th|English only", buf.String(); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+// wrappedI18n hides the underlying Goi18n to test Localizer with other I18n implementations.
+type wrappedI18n struct {
+	I18n
+}
+
+func TestLocalizer_OtherI18n(t *testing.T) {
+	testName := "TestLocalizer_OtherI18n"
+	i18n := wrappedI18n{_buildI18nLocalizer(t, testName)}
+	localizer := &Localizer{i18n: i18n, locales: []string{"fr", "vi"}}
+	if e, v := "Français seulement", localizer.Localize("only_fr"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "2 items", localizer.Plural("items", 2, LocalizeConfig{TemplateData: map[string]interface{}{"n": 2}}); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "2nd", localizer.Ordinal("rank", 2, &LocalizeConfig{TemplateData: map[string]interface{}{"n": 2}}); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "English only", (&Localizer{i18n: i18n}).Localize("only_en"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestNewLocalizer_CustomI18n(t *testing.T) {
+	testName := "TestNewLocalizer_CustomI18n"
+	localizer := NewLocalizer(customI18n{}, "vi", "en")
+	if e, v := "vi", localizer.Locale(); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "vi:hello", localizer.Localize("hello"); v != e {
+		t.Fatalf("%s failed, expected [%s] but received [%s]", testName, e, v)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"text/template"
 
 	"github.com/btnguyen2k/consu/reddo"
@@ -48,8 +51,57 @@ type Message struct {
 
 	// Other is the message's content for the CLDR plural form "other".
 	Other string
+
+	// Exact holds the message's content for explicit plural counts, keyed by the number (e.g. keys "=0" and "=42" of
+	// a message in language files are stored as "0" and "42"). An exact match of the count is picked before the plural
+	// form of the count's plural category.
+	//
+	// Available since v0.3.0
+	Exact map[string]string
+
+	// LeftDelim and RightDelim are the action delimiters of the message's templates (e.g. "<<" and ">>"), empty means
+	// the default delimiters "{{" and "}}".
+	//
+	// Available since v0.3.0
+	LeftDelim, RightDelim string
+
+	// Syntax is the syntax of the message's content: SyntaxTemplate (or empty) for Go text/template, SyntaxIcu for ICU
+	// MessageFormat. A message in ICU MessageFormat has its whole content, including plural and select variants, in
+	// field Other (e.g. "{count, plural, =0 {No files} one {# file} other {# files}}"); other plural forms are ignored.
+	//
+	// Available since v0.3.0
+	Syntax string
+
+	// Variants holds the message's select variants by key (e.g. "female", "formal"), picked by LocalizeConfig.Select.
+	// A variant has its own content and plural forms; the message itself is the variant "other", which is used if
+	// LocalizeConfig.Select is empty or does not match any variant. Variants inherit the message's delimiters and syntax.
+	//
+	// Available since v0.3.0
+	Variants map[string]*Message
+
+	// compiled holds the compiled templates of the message (*messageTemplates), see function compile.
+	compiled atomic.Value
+
+	// file is the language file the message is loaded from, empty if not loaded from a file (e.g. BuildI18nFromBytes).
+	file string
+}
+
+// messageTemplates holds the compiled templates of a message, so that they are parsed only once.
+type messageTemplates struct {
+	templates map[string]*template.Template // compiled templates, by content
+	errors    map[string]error              // template syntax errors, by content
+	icu       []icuNode                     // parsed content of a message in ICU MessageFormat
+	icuErr    error                         // syntax error of a message in ICU MessageFormat
 }
 
+// Syntaxes of message content, see Message.Syntax.
+//
+// Available since v0.3.0
+const (
+	SyntaxTemplate = "template"
+	SyntaxIcu      = "icu"
+)
+
 func (m *Message) parseMessageAttr(k string, v interface{}) error {
 	var ok bool
 	temp := strings.TrimSpace(strings.ToLower(k))
@@ -82,8 +134,35 @@ func (m *Message) parseMessageAttr(k string, v interface{}) error {
 		if m.Other, ok = v.(string); !ok {
 			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
 		}
+	case "leftdelim":
+		if m.LeftDelim, ok = v.(string); !ok {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
+	case "rightdelim":
+		if m.RightDelim, ok = v.(string); !ok {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
+	case "syntax":
+		if m.Syntax, ok = v.(string); !ok {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
+	case "select":
+		return m.parseVariants(k, v)
 	default:
-		return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		if !strings.HasPrefix(temp, "=") {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
+		// explicit plural count, e.g. "=0" or "=42"
+		number := strings.TrimSpace(temp[1:])
+		if _, err := strconv.ParseFloat(number, 64); err != nil {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
+		if m.Exact == nil {
+			m.Exact = make(map[string]string)
+		}
+		if m.Exact[number], ok = v.(string); !ok {
+			return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+		}
 	}
 	return nil
 }
@@ -99,7 +178,6 @@ func (m *Message) parse(data interface{}) error {
 	case reflect.String:
 		// message data is a simple string: it is the localized message itself
 		m.Other = strings.TrimSpace(data.(string))
-		return nil
 	case reflect.Map:
 		it := reflect.ValueOf(data).MapRange()
 		for it.Next() {
@@ -109,12 +187,73 @@ func (m *Message) parse(data interface{}) error {
 				return err
 			}
 		}
-		return nil
 	default:
 		return fmt.Errorf("error parsing message data '%s' (type %T)", m.Id, data)
 	}
+	for _, variant := range m.Variants {
+		if variant.LeftDelim == "" && variant.RightDelim == "" {
+			variant.LeftDelim, variant.RightDelim = m.LeftDelim, m.RightDelim
+		}
+		if variant.Syntax == "" {
+			variant.Syntax = m.Syntax
+		}
+		if err := variant.validateSyntax(); err != nil {
+			return err
+		}
+	}
+	return m.validateSyntax()
 }
 
+// parseVariants parses the select variants of the message, data must be a map of variant keys to message data.
+func (m *Message) parseVariants(k string, data interface{}) error {
+	if data == nil || reflect.TypeOf(data).Kind() != reflect.Map {
+		return fmt.Errorf("error parsing message data at '%s.%s'", m.Id, k)
+	}
+	m.Variants = make(map[string]*Message)
+	it := reflect.ValueOf(data).MapRange()
+	for it.Next() {
+		key, _ := it.Key().Interface().(string)
+		key = strings.TrimSpace(key)
+		variant := &Message{Id: m.Id}
+		if err := variant.parse(it.Value().Interface()); err != nil {
+			return err
+		}
+		if key == "" || len(variant.Variants) > 0 {
+			// nested select variants are not supported
+			return fmt.Errorf("error parsing message data at '%s.%s.%s'", m.Id, k, key)
+		}
+		m.Variants[key] = variant
+	}
+	return nil
+}
+
+// variant returns the select variant picked by cfg.Select, or the message itself if there is no match.
+func (m *Message) variant(cfg *LocalizeConfig) *Message {
+	if cfg != nil && cfg.Select != "" {
+		if variant := m.Variants[cfg.Select]; variant != nil {
+			return variant
+		}
+	}
+	return m
+}
+
+// validateSyntax checks the message's syntax, and the content of a message in ICU MessageFormat.
+func (m *Message) validateSyntax() error {
+	switch strings.ToLower(m.Syntax) {
+	case "", SyntaxTemplate:
+		return nil
+	case SyntaxIcu:
+		m.Syntax = SyntaxIcu
+		if _, err := parseIcuMessage(m.Other); err != nil {
+			return fmt.Errorf("error parsing message data '%s': %w", m.Id, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("error parsing message data at '%s.syntax': unknown syntax [%s]", m.Id, m.Syntax)
+}
+
+// pluralFormTemplate returns the template of the plural form selected by cfg.PluralCount, using the fixed count-to-form
+// mapping of goyai v0.2.x (see I18nOptions.LegacyPluralRules).
 func (m *Message) pluralFormTemplate(cfg *LocalizeConfig) string {
 	var err error
 	var pluralForm int64 = -1
@@ -155,10 +294,219 @@ func (m *Message) pluralFormTemplate(cfg *LocalizeConfig) string {
 	}
 }
 
-func (m *Message) render(cfg *LocalizeConfig) string {
-	msg := m.pluralFormTemplate(cfg)
-	t := template.New(m.Id)
-	if _, err := t.Parse(msg); err != nil {
+// pluralForm returns the message's content for a CLDR plural category.
+func (m *Message) pluralForm(category string) string {
+	switch category {
+	case pluralZero:
+		return m.Zero
+	case pluralOne:
+		return m.One
+	case pluralTwo:
+		return m.Two
+	case pluralFew:
+		return m.Few
+	case pluralMany:
+		return m.Many
+	default:
+		return m.Other
+	}
+}
+
+// hasPluralForms returns true if the message has content for any plural form other than "other", or for explicit
+// plural counts.
+func (m *Message) hasPluralForms() bool {
+	return m.Zero != "" || m.One != "" || m.Two != "" || m.Few != "" || m.Many != "" || len(m.Exact) > 0
+}
+
+// exactTemplate returns the template of the explicit plural count equal to count, if any.
+func (m *Message) exactTemplate(count interface{}) (string, bool) {
+	if len(m.Exact) == 0 || count == nil {
+		return "", false
+	}
+	number, ok := icuNumber(count)
+	if !ok {
+		return "", false
+	}
+	for key, msg := range m.Exact {
+		if exact, err := strconv.ParseFloat(key, 64); err == nil && exact == number && msg != "" {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// exactCounts returns the explicit plural counts of the message in ascending order.
+func (m *Message) exactCounts() []string {
+	counts := make([]string, 0, len(m.Exact))
+	for key := range m.Exact {
+		counts = append(counts, key)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		x, _ := strconv.ParseFloat(counts[i], 64)
+		y, _ := strconv.ParseFloat(counts[j], 64)
+		return x < y
+	})
+	return counts
+}
+
+// placeholderTemplate returns the form "other" of the message with custom delimiters replaced by the default ones (or
+// the placeholders of the arguments of a message in ICU MessageFormat), so that placeholders of the message can be
+// mapped to params of Localize (see LocalizeConfig.TemplateData).
+func (m *Message) placeholderTemplate() string {
+	if m.Syntax == SyntaxIcu {
+		var sb strings.Builder
+		for _, name := range icuArgNames(m.templates().icu, nil) {
+			sb.WriteString(icuPlaceholder(name))
+		}
+		return sb.String()
+	}
+	return m.defaultDelims(m.Other)
+}
+
+// positionalTemplateData maps strings/numbers/booleans params to the placeholders of the select variant picked by cfg,
+// which is the one rendered (see I18n.Localize).
+func (m *Message) positionalTemplateData(cfg *LocalizeConfig, params ...interface{}) map[string]interface{} {
+	return _buildTemplateData(m.variant(cfg).placeholderTemplate(), params...)
+}
+
+// defaultDelims replaces custom delimiters of a content of the message with the default ones "{{" and "}}".
+func (m *Message) defaultDelims(content string) string {
+	if m.LeftDelim == "" && m.RightDelim == "" {
+		return content
+	}
+	left, right := m.LeftDelim, m.RightDelim
+	if left == "" {
+		left = "{{"
+	}
+	if right == "" {
+		right = "}}"
+	}
+	return strings.NewReplacer(left, "{{", right, "}}").Replace(content)
+}
+
+// selectTemplate returns the template of the plural form selected by cfg.OrdinalCount or cfg.PluralCount.
+//
+// Explicit plural counts (see Message.Exact) are matched first. If cfg.OrdinalCount is specified, the plural category
+// is determined by the CLDR ordinal plural rules of the selector.
+// Otherwise, if plural is nil or in legacy mode, function pluralFormTemplate is used to pick the plural form; if not,
+// the plural category is determined by the CLDR cardinal plural rules of the selector.
+func (m *Message) selectTemplate(plural *pluralSelector, cfg *LocalizeConfig) string {
+	if cfg != nil {
+		count := cfg.PluralCount
+		if cfg.OrdinalCount != nil {
+			count = cfg.OrdinalCount
+		}
+		if msg, ok := m.exactTemplate(count); ok {
+			return msg
+		}
+	}
+	if plural != nil && cfg != nil && cfg.OrdinalCount != nil {
+		return m.categoryTemplate(plural.ordinal, cfg.OrdinalCount)
+	}
+	if plural == nil || plural.legacy {
+		return m.pluralFormTemplate(cfg)
+	}
+	var count interface{}
+	if cfg != nil {
+		count = cfg.PluralCount
+	}
+	return m.categoryTemplate(plural.cardinal, count)
+}
+
+// categoryTemplate returns the template of the plural category of count, falling back to the form "other" if the
+// message does not have content for the category.
+func (m *Message) categoryTemplate(rules *pluralRules, count interface{}) string {
+	category := pluralOther
+	if count != nil {
+		if operands, ok := newPluralOperands(count); ok {
+			category = rules.category(operands)
+		}
+	}
+	if msg := m.pluralForm(category); msg != "" {
+		return msg
+	}
+	return m.Other
+}
+
+// compile parses the templates of all plural forms of the message and its variants (or the content of a message in
+// ICU MessageFormat) and caches them for rendering. The first syntax error, if any, is returned.
+func (m *Message) compile() (*messageTemplates, error) {
+	compiled := &messageTemplates{templates: make(map[string]*template.Template), errors: make(map[string]error)}
+	var firstErr error
+	if m.Syntax == SyntaxIcu {
+		compiled.icu, compiled.icuErr = parseIcuMessage(m.Other)
+		if compiled.icuErr != nil {
+			firstErr = fmt.Errorf("error parsing message [%s]: %w", m.Id, compiled.icuErr)
+		}
+	} else {
+		contents := []string{m.Zero, m.One, m.Two, m.Few, m.Many, m.Other}
+		for _, count := range m.exactCounts() {
+			contents = append(contents, m.Exact[count])
+		}
+		for _, content := range contents {
+			if _, exists := compiled.templates[content]; exists || content == "" || compiled.errors[content] != nil {
+				continue
+			}
+			t, err := template.New(m.Id).Delims(m.LeftDelim, m.RightDelim).Parse(content)
+			if err != nil {
+				compiled.errors[content] = err
+				if firstErr == nil {
+					firstErr = fmt.Errorf("error parsing message [%s]: %w", m.Id, err)
+				}
+				continue
+			}
+			compiled.templates[content] = t
+		}
+	}
+	m.compiled.Store(compiled)
+	keys := make([]string, 0, len(m.Variants))
+	for key := range m.Variants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := m.Variants[key].compile(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return compiled, firstErr
+}
+
+// templates returns the compiled templates of the message, compiling them on first use.
+func (m *Message) templates() *messageTemplates {
+	if compiled, ok := m.compiled.Load().(*messageTemplates); ok {
+		return compiled
+	}
+	compiled, _ := m.compile()
+	return compiled
+}
+
+// template returns the compiled template of a content of the message.
+func (m *Message) template(content string) (*template.Template, error) {
+	compiled := m.templates()
+	if t := compiled.templates[content]; t != nil {
+		return t, nil
+	}
+	if err := compiled.errors[content]; err != nil {
+		return nil, err
+	}
+	// content is not one of the compiled ones, e.g. fields of the message were changed after compiling
+	return template.New(m.Id).Delims(m.LeftDelim, m.RightDelim).Parse(content)
+}
+
+func (m *Message) render(plural *pluralSelector, cfg *LocalizeConfig) string {
+	if variant := m.variant(cfg); variant != m {
+		return variant.render(plural, cfg)
+	}
+	if m.Syntax == SyntaxIcu {
+		return m.renderIcu(plural, cfg)
+	}
+	msg := m.selectTemplate(plural, cfg)
+	if msg == "" {
+		return ""
+	}
+	t, err := m.template(msg)
+	if err != nil {
 		log.Printf("[WARN] error parsing message [%s]: %s", m.Id, err)
 		return msg
 	}
@@ -262,7 +262,7 @@ func TestMessage_render(t *testing.T) {
 	expected := map[int]string{-2: other, -1: other, 0: zero, 1: one, 2: two, 3: many, 4: many}
 	for k, _e := range expected {
 		cfg.PluralCount = k
-		v := msg.render(cfg)
+		v := msg.render(nil, cfg)
 		e := strings.ReplaceAll(_e, "{{.data}}", "value")
 		if v != e {
 			t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, k, e, v)
@@ -270,6 +270,54 @@ func TestMessage_render(t *testing.T) {
 	}
 }
 
+func TestMessage_render_Delims(t *testing.T) {
+	testName := "TestMessage_render_Delims"
+	data := map[string]interface{}{"leftDelim": "<<", "rightDelim": ">>", "other": "Hello <<.name>>, {{.literal}}"}
+	msg, err := ParseMessage("mid", data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if msg.LeftDelim != "<<" || msg.RightDelim != ">>" {
+		t.Fatalf("%s failed, expect delimiters [<< >>] but received [%s %s]", testName, msg.LeftDelim, msg.RightDelim)
+	}
+	cfg := &LocalizeConfig{TemplateData: map[string]interface{}{"name": "Thanh"}}
+	if e, v := "Hello Thanh, {{.literal}}", msg.render(nil, cfg); v != e {
+		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := "Hello {{.name}}, {{.literal}}", msg.placeholderTemplate(); v != e {
+		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
+	}
+}
+
+func TestMessage_positionalTemplateData(t *testing.T) {
+	testName := "TestMessage_positionalTemplateData"
+	yamlContent := `
+en:
+  replied:
+    other: "{{.count}} people replied to {{.name}}"
+    select:
+      female: "{{.name}} got replies from {{.count}} people"
+`
+	i18n, err := BuildI18nFromBytes([]byte(yamlContent), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	msg := i18n.(*Goi18n).messagesStore["en"]["replied"]
+	testCases := []struct {
+		cfg      *LocalizeConfig
+		expected map[string]interface{}
+	}{
+		{nil, map[string]interface{}{"count": 3, "name": "Ann"}},
+		{&LocalizeConfig{Select: "male"}, map[string]interface{}{"count": 3, "name": "Ann"}},
+		{&LocalizeConfig{Select: "female"}, map[string]interface{}{"name": 3, "count": "Ann"}},
+	}
+	for i, testCase := range testCases {
+		if v := msg.positionalTemplateData(testCase.cfg, 3, "Ann"); !reflect.DeepEqual(v, testCase.expected) {
+			t.Fatalf("%s failed for case #%d: expected %#v but received %#v", testName, i, testCase.expected, v)
+		}
+	}
+}
+
 func TestMessage_render_empty(t *testing.T) {
 	testName := "TestMessage_render_empty"
 	msgId := "mid"
@@ -278,7 +326,7 @@ func TestMessage_render_empty(t *testing.T) {
 	if msg == nil || err != nil {
 		t.Fatalf("%s failed: %s", testName, err)
 	}
-	if e, v := "", msg.render(nil); v != e {
+	if e, v := "", msg.render(nil, nil); v != e {
 		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
 	}
 }
@@ -293,10 +341,197 @@ func TestMessage_render_error(t *testing.T) {
 	if msg == nil || err != nil {
 		t.Fatalf("%s failed: %s", testName, err)
 	}
-	if e, v := invalidTemplate, msg.render(nil); v != e {
+	if e, v := invalidTemplate, msg.render(nil, nil); v != e {
 		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
 	}
-	if e, v := validTemplate, msg.render(&LocalizeConfig{PluralCount: 0}); v != e {
+	if e, v := validTemplate, msg.render(nil, &LocalizeConfig{PluralCount: 0}); v != e {
 		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
 	}
 }
+
+func TestMessage_selectTemplate(t *testing.T) {
+	testName := "TestMessage_selectTemplate"
+	msgId := "mid"
+	data := map[string]interface{}{"Zero": zero, "One": one, "Two": two, "Few": few, "Many": many, "Other": other}
+	msg, err := ParseMessage(msgId, data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	plural := newPluralSelector("ru", false)
+	expected := map[interface{}]string{"none": other, 0: many, 1: one, 2: few, 4: few, 5: many, 21: one}
+	for k, e := range expected {
+		v := msg.selectTemplate(plural, &LocalizeConfig{PluralCount: k})
+		if v != e {
+			t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, k, e, v)
+		}
+	}
+	if e, v := other, msg.selectTemplate(plural, nil); v != e {
+		t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, "<nil>", e, v)
+	}
+}
+
+func TestMessage_selectTemplate_FallbackToOther(t *testing.T) {
+	testName := "TestMessage_selectTemplate_FallbackToOther"
+	msgId := "mid"
+	data := map[string]interface{}{"One": one, "Other": other}
+	msg, err := ParseMessage(msgId, data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	plural := newPluralSelector("ru", false)
+	if e, v := other, msg.selectTemplate(plural, &LocalizeConfig{PluralCount: 3}); v != e {
+		t.Fatalf("%s failed (%v), expect [%s] but received [%s]", testName, 3, e, v)
+	}
+}
+
+func TestMessage_selectTemplate_Ordinal(t *testing.T) {
+	testName := "TestMessage_selectTemplate_Ordinal"
+	msgId := "mid"
+	data := map[string]interface{}{"One": "{{.n}}st", "Two": "{{.n}}nd", "Few": "{{.n}}rd", "Other": "{{.n}}th"}
+	msg, err := ParseMessage(msgId, data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	expected := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"}
+	for _, legacy := range []bool{false, true} {
+		plural := newPluralSelector("en", legacy)
+		for n, e := range expected {
+			cfg := &LocalizeConfig{OrdinalCount: n, PluralCount: 1, TemplateData: map[string]interface{}{"n": n}}
+			if v := msg.render(plural, cfg); v != e {
+				t.Fatalf("%s failed (%v/legacy=%v), expect [%s] but received [%s]", testName, n, legacy, e, v)
+			}
+		}
+	}
+}
+
+func TestMessage_render_Select(t *testing.T) {
+	testName := "TestMessage_render_Select"
+	data := map[string]interface{}{
+		"leftDelim":  "<<",
+		"rightDelim": ">>",
+		"one":        "<<.name>> replied to their post",
+		"other":      "<<.name>> and <<.count>> others replied to their post",
+		"select": map[string]interface{}{
+			"female": map[string]interface{}{"one": "<<.name>> replied to her post", "other": "<<.name>> and <<.count>> others replied to her post"},
+			"male":   "<<.name>> replied to his post",
+		},
+	}
+	msg, err := ParseMessage("mid", data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(msg.Variants); v != e {
+		t.Fatalf("%s failed: expected %d variants but received %d", testName, e, v)
+	}
+	plural := newPluralSelector("en", false)
+	templateData := map[string]interface{}{"name": "Ann", "count": 2}
+	testCases := []struct {
+		selectKey string
+		count     interface{}
+		expected  string
+	}{
+		{"female", 1, "Ann replied to her post"},
+		{"female", 2, "Ann and 2 others replied to her post"},
+		{"male", 1, "Ann replied to his post"},
+		{"male", 2, "Ann replied to his post"},
+		{"robot", 1, "Ann replied to their post"},
+		{"", 2, "Ann and 2 others replied to their post"},
+	}
+	for _, tc := range testCases {
+		cfg := &LocalizeConfig{TemplateData: templateData, PluralCount: tc.count, Select: tc.selectKey}
+		if v := msg.render(plural, cfg); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%v]: expected %#v but received %#v", testName, tc.selectKey, tc.count, tc.expected, v)
+		}
+	}
+
+	icuMsg, err := ParseMessage("mid", map[string]interface{}{"syntax": "icu", "other": "{gender, select, female {her} other {their}} post"})
+	if icuMsg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "her post", icuMsg.render(plural, &LocalizeConfig{Select: "female"}); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	for _, invalid := range []interface{}{
+		map[string]interface{}{"other": "x", "select": "female"},
+		map[string]interface{}{"other": "x", "select": map[string]interface{}{"female": 1}},
+		map[string]interface{}{"other": "x", "select": map[string]interface{}{"female": map[string]interface{}{"other": "y", "select": map[string]interface{}{"formal": "z"}}}},
+	} {
+		if _, err := ParseMessage("mid", invalid); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
+
+func TestMessage_selectTemplate_Exact(t *testing.T) {
+	testName := "TestMessage_selectTemplate_Exact"
+	data := map[string]interface{}{"=0": "No new mail", "= 1": "Your one and only order", "=42": "The answer", "One": one, "Other": other}
+	msg, err := ParseMessage("mid", data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := []string{"0", "1", "42"}, msg.exactCounts(); !reflect.DeepEqual(v, e) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	for _, legacy := range []bool{false, true} {
+		plural := newPluralSelector("en", legacy)
+		expected := map[interface{}]string{0: "No new mail", 1: "Your one and only order", "1.0": "Your one and only order", 42: "The answer", 42.0: "The answer", 2: other, "none": other}
+		for k, e := range expected {
+			if v := msg.selectTemplate(plural, &LocalizeConfig{PluralCount: k}); v != e {
+				t.Fatalf("%s failed (%v/%v), expect [%s] but received [%s]", testName, legacy, k, e, v)
+			}
+		}
+	}
+	if e, v := "The answer", msg.selectTemplate(newPluralSelector("en", false), &LocalizeConfig{OrdinalCount: 42}); v != e {
+		t.Fatalf("%s failed, expect [%s] but received [%s]", testName, e, v)
+	}
+	for _, invalid := range []map[string]interface{}{{"=abc": "x"}, {"=": "x"}, {"=1": 1}} {
+		if _, err := ParseMessage("mid", invalid); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
+
+func TestMessage_compile(t *testing.T) {
+	testName := "TestMessage_compile"
+	data := map[string]interface{}{"one": "One {{.data}}", "other": "Other {{.data}}", "=0": "Other {{.data}}",
+		"select": map[string]interface{}{"formal": "Formal {{.data}}"}}
+	msg, err := ParseMessage("mid", data)
+	if msg == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	compiled, err := msg.compile()
+	if compiled == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 2, len(compiled.templates); v != e {
+		t.Fatalf("%s failed: expected %d compiled templates but received %d", testName, e, v)
+	}
+	if compiled != msg.templates() || msg.Variants["formal"].templates() == nil {
+		t.Fatalf("%s failed: compiled templates are not cached", testName)
+	}
+	tpl, _ := msg.template("Other {{.data}}")
+	if tpl2, _ := msg.template("Other {{.data}}"); tpl == nil || tpl != tpl2 {
+		t.Fatalf("%s failed: compiled templates are not reused", testName)
+	}
+	cfg := &LocalizeConfig{TemplateData: map[string]interface{}{"data": "x"}, Select: "formal"}
+	if e, v := "Formal x", msg.render(nil, cfg); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	for _, invalid := range []interface{}{
+		"This template is {{.invalid>",
+		map[string]interface{}{"one": "{{.name|upper}}", "other": "ok"},
+		map[string]interface{}{"=1": "{{if}}", "other": "ok"},
+		map[string]interface{}{"other": "ok", "select": map[string]interface{}{"formal": "{{end}}"}},
+		map[string]interface{}{"leftDelim": "<<", "rightDelim": ">>", "other": "<<.name"},
+	} {
+		msg, err := ParseMessage("mid", invalid)
+		if msg == nil || err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if _, err := msg.compile(); err == nil {
+			t.Fatalf("%s failed: expected error for %#v", testName, invalid)
+		}
+	}
+}
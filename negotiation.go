@@ -0,0 +1,184 @@
+package goyai
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Confidence indicates how well a matched locale fits the preferred locales, used by function MatchLocale.
+//
+// Available since v0.3.0
+type Confidence int
+
+const (
+	// ConfidenceNone indicates that none of the preferred locales is supported, the default locale is returned.
+	ConfidenceNone Confidence = iota
+
+	// ConfidenceLow indicates that the matched locale has the same language but differs in region or variant from the
+	// preferred locale (e.g. "pt-PT" for "pt-BR"), or that the match is made by the wildcard "*".
+	ConfidenceLow
+
+	// ConfidenceHigh indicates that the matched locale is a parent or a child of the preferred locale
+	// (e.g. "en" for "en-US", or "en-US" for "en").
+	ConfidenceHigh
+
+	// ConfidenceExact indicates that the matched locale is exactly the preferred locale.
+	ConfidenceExact
+)
+
+// String implements fmt.Stringer.
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "Low"
+	case ConfidenceHigh:
+		return "High"
+	case ConfidenceExact:
+		return "Exact"
+	default:
+		return "None"
+	}
+}
+
+// ParseAcceptLanguage parses the value of an Accept-Language HTTP header (e.g. "da, en-GB;q=0.8, en;q=0.7") and returns
+// the list of language tags ordered by their quality values, highest first. Language tags with the same quality value
+// retain their order in the header. Language tags with zero quality value or that are not well-formed are ignored.
+// Language tags are returned in canonical form, the wildcard "*" is returned as-is.
+//
+// Available since v0.3.0
+func ParseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag string
+		q   float64
+	}
+	tags := make([]weightedTag, 0)
+	for _, entry := range strings.Split(header, ",") {
+		tokens := strings.Split(entry, ";")
+		tag := strings.TrimSpace(tokens[0])
+		q := 1.0
+		for _, param := range tokens[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(strings.ToLower(kv[0])) == "q" {
+				var err error
+				if q, err = strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		if tag != "*" {
+			t, err := ParseLocaleTag(tag)
+			if err != nil {
+				continue
+			}
+			tag = t.String()
+		}
+		tags = append(tags, weightedTag{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// MatchLocale returns the available locale of an I18n instance that best matches the preferred locales, and the
+// confidence of the match. A preferred locale can be either a BCP 47 language tag (e.g. "en-US") or the value of an
+// Accept-Language HTTP header (e.g. "da, en-GB;q=0.8, en;q=0.7"), see ParseAcceptLanguage.
+//
+// Preferred locales are checked in order (by quality value, then by position), the first one that matches an
+// available locale wins. If none of the preferred locales matches, the default locale is returned with ConfidenceNone.
+// If the preferred locales contain the wildcard "*", the default locale (or the first available locale if the default
+// locale is not loaded) is returned with ConfidenceLow instead. For I18n implementations other than goyai's, the
+// default locale is not known: an empty LocaleInfo, or the first available locale for the wildcard, is returned instead.
+//
+// Available since v0.3.0
+func MatchLocale(i18n I18n, preferred ...string) (LocaleInfo, Confidence) {
+	locales := i18n.AvailableLocales()
+	available := make([]string, 0, len(locales))
+	for _, localeInfo := range locales {
+		available = append(available, canonicalLocale(localeInfo.Id))
+	}
+	wildcard := false
+	for _, pref := range preferred {
+		for _, tag := range ParseAcceptLanguage(pref) {
+			if tag == "*" {
+				wildcard = true
+				continue
+			}
+			if locale, confidence := matchLocale(tag, available); confidence != ConfidenceNone {
+				for i := range available {
+					if available[i] == locale {
+						return locales[i], confidence
+					}
+				}
+			}
+		}
+	}
+	confidence := ConfidenceNone
+	if wildcard {
+		confidence = ConfidenceLow
+	}
+	if impl, ok := i18n.(*Goi18n); ok {
+		if localeInfo := impl.locales[impl.defaultLocale]; localeInfo != nil {
+			return *localeInfo, confidence
+		}
+		if !wildcard || len(locales) == 0 {
+			tag, _ := ParseLocaleTag(impl.defaultLocale)
+			return LocaleInfo{Id: impl.defaultLocale, DisplayName: impl.defaultLocale, Tag: tag}, ConfidenceNone
+		}
+	}
+	if wildcard && len(locales) > 0 {
+		return locales[0], ConfidenceLow
+	}
+	return LocaleInfo{}, ConfidenceNone
+}
+
+// isAncestorLocale returns true if ancestor is a parent, or a parent of a parent..., of locale.
+func isAncestorLocale(ancestor, locale string) bool {
+	for l := parentLocale(locale); l != ""; l = parentLocale(l) {
+		if l == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLocale finds the available locale that best matches a preferred locale.
+//
+// Available locales are checked in order: the preferred locale itself (ConfidenceExact), then its closest parent, then
+// its children (ConfidenceHigh), and finally locales of the same language and script (ConfidenceLow).
+func matchLocale(preferred string, available []string) (string, Confidence) {
+	for _, locale := range available {
+		if locale == preferred {
+			return locale, ConfidenceExact
+		}
+	}
+	for l := parentLocale(preferred); l != ""; l = parentLocale(l) {
+		for _, locale := range available {
+			if locale == l {
+				return locale, ConfidenceHigh
+			}
+		}
+	}
+	for _, locale := range available {
+		if isAncestorLocale(preferred, locale) {
+			return locale, ConfidenceHigh
+		}
+	}
+	prefTag, err := ParseLocaleTag(preferred)
+	if err != nil {
+		return "", ConfidenceNone
+	}
+	for _, locale := range available {
+		tag, err := ParseLocaleTag(locale)
+		if err == nil && tag.Language == prefTag.Language && (tag.Script == prefTag.Script || tag.Script == "" || prefTag.Script == "") {
+			return locale, ConfidenceLow
+		}
+	}
+	return "", ConfidenceNone
+}
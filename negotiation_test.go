@@ -0,0 +1,87 @@
+package goyai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	testName := "TestParseAcceptLanguage"
+	testCases := map[string][]string{
+		"":                                    {},
+		"da, en-gb;q=0.8, en;q=0.7":           {"da", "en-GB", "en"},
+		"en;q=0.5, fr_ca , *;q=0.1, de;q=0.5": {"fr-CA", "en", "de", "*"},
+		"vi;q=0, ja;q=abc, zh-hant;Q=0.9":     {"zh-Hant"},
+		"en2, en-US;q=1.5, pt-BR;level=1":     {"pt-BR"},
+	}
+	for header, expected := range testCases {
+		if v := ParseAcceptLanguage(header); !reflect.DeepEqual(v, expected) {
+			t.Fatalf("%s failed (%s), expected %#v but received %#v", testName, header, expected, v)
+		}
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	testName := "TestMatchLocale"
+	available := []string{"en", "en-GB", "pt-PT", "sr-Cyrl", "zh-Hant-TW"}
+	testCases := []struct {
+		preferred  string
+		locale     string
+		confidence Confidence
+	}{
+		{"en-GB", "en-GB", ConfidenceExact},
+		{"en-US", "en", ConfidenceHigh},
+		{"en-GB-oxendict", "en-GB", ConfidenceHigh},
+		{"pt", "pt-PT", ConfidenceHigh},
+		{"zh-Hant", "zh-Hant-TW", ConfidenceHigh},
+		{"pt-BR", "pt-PT", ConfidenceLow},
+		{"sr", "sr-Cyrl", ConfidenceHigh},
+		{"sr-Latn", "", ConfidenceNone},
+		{"zh-Hans-CN", "", ConfidenceNone},
+		{"vi", "", ConfidenceNone},
+	}
+	for _, testCase := range testCases {
+		locale, confidence := matchLocale(testCase.preferred, available)
+		if locale != testCase.locale || confidence != testCase.confidence {
+			t.Fatalf("%s failed (%s), expected [%s/%s] but received [%s/%s]", testName, testCase.preferred, testCase.locale, testCase.confidence, locale, confidence)
+		}
+	}
+}
+
+// customI18n is an I18n implementation other than goyai's, e.g. a mock.
+type customI18n struct {
+	locales []LocaleInfo
+}
+
+func (c customI18n) Localize(locale, msgId string, params ...interface{}) string {
+	return locale + ":" + msgId
+}
+
+func (c customI18n) Localise(locale, msgId string, params ...interface{}) string {
+	return c.Localize(locale, msgId, params...)
+}
+
+func (c customI18n) AvailableLocales() []LocaleInfo {
+	return c.locales
+}
+
+func TestMatchLocale_CustomI18n(t *testing.T) {
+	testName := "TestMatchLocale_CustomI18n"
+	i18n := customI18n{locales: []LocaleInfo{{Id: "en", DisplayName: "English"}, {Id: "pt_PT", DisplayName: "Português"}}}
+	testCases := []struct {
+		preferred  []string
+		locale     string
+		confidence Confidence
+	}{
+		{[]string{"en"}, "en", ConfidenceExact},
+		{[]string{"pt-BR, en;q=0.5"}, "pt_PT", ConfidenceLow},
+		{[]string{"vi, *;q=0.1"}, "en", ConfidenceLow},
+		{[]string{"vi"}, "", ConfidenceNone},
+	}
+	for _, testCase := range testCases {
+		locale, confidence := MatchLocale(i18n, testCase.preferred...)
+		if locale.Id != testCase.locale || confidence != testCase.confidence {
+			t.Fatalf("%s failed (%v), expected [%s/%s] but received [%s/%s]", testName, testCase.preferred, testCase.locale, testCase.confidence, locale.Id, confidence)
+		}
+	}
+}
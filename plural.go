@@ -0,0 +1,350 @@
+package goyai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/btnguyen2k/consu/reddo"
+)
+
+// CLDR plural categories.
+const (
+	pluralZero  = "zero"
+	pluralOne   = "one"
+	pluralTwo   = "two"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+// pluralCategories lists the CLDR plural categories in canonical order.
+var pluralCategories = []string{pluralZero, pluralOne, pluralTwo, pluralFew, pluralMany, pluralOther}
+
+// pluralOperands holds the CLDR plural operands of a number.
+//
+// See https://unicode.org/reports/tr35/tr35-numbers.html#Operands for more information.
+type pluralOperands struct {
+	i   uint64 // integer digits of n
+	v   uint64 // number of visible fraction digits in n, with trailing zeros
+	w   uint64 // number of visible fraction digits in n, without trailing zeros
+	f   uint64 // visible fraction digits in n, with trailing zeros
+	t   uint64 // visible fraction digits in n, without trailing zeros
+	e   uint64 // exponent of the power of 10 used in compact decimal formatting
+	big bool   // true if integer digits of n do not fit in i, in which case i holds the last maxPluralDigits digits
+}
+
+// maxPluralDigits is the maximum number of digits of plural operands i, f and t.
+const maxPluralDigits = 18
+
+var reDecimal = regexp.MustCompile(`^[+-]?(\d*)(?:\.(\d*))?(?:[cCeE](\d+))?$`)
+
+// newPluralOperands builds plural operands from a count value.
+//
+// count can be an integer, a floating point number, a big number (*big.Int, *big.Float), a json.Number or a string
+// representing a decimal number (e.g. "1.0", "1,000", "1.2e3"). The sign of count is ignored. If count is not a number,
+// this function returns false.
+//
+// Note: a floating point number is formatted using the smallest number of digits necessary to represent it, e.g.
+// 1.0 is treated as "1" and 1.50 as "1.5". Use a string to preserve trailing zeros of the fraction digits.
+func newPluralOperands(count interface{}) (*pluralOperands, bool) {
+	switch v := count.(type) {
+	case string:
+		return parsePluralOperands(v)
+	case json.Number:
+		return parsePluralOperands(v.String())
+	case *big.Int:
+		if v != nil {
+			return parsePluralOperands(v.String())
+		}
+		return nil, false
+	case *big.Float:
+		if v != nil && !v.IsInf() {
+			return parsePluralOperands(v.Text('f', -1))
+		}
+		return nil, false
+	}
+	rv := reflect.ValueOf(count)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return parsePluralOperands(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return parsePluralOperands(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		fv := rv.Float()
+		if math.IsNaN(fv) || math.IsInf(fv, 0) {
+			return nil, false
+		}
+		return parsePluralOperands(strconv.FormatFloat(fv, 'f', -1, rv.Type().Bits()))
+	case reflect.String:
+		return parsePluralOperands(rv.String())
+	}
+	n, err := reddo.ToInt(count)
+	if err != nil {
+		return nil, false
+	}
+	return parsePluralOperands(strconv.FormatInt(n, 10))
+}
+
+// parsePluralOperands builds plural operands from a string representing a decimal number.
+//
+// Grouping separators (",") are ignored, and the number can be in compact decimal format (e.g. "1.2c3" or "1.2e3", which
+// is 1200 with exponent 3).
+func parsePluralOperands(input string) (*pluralOperands, bool) {
+	match := reDecimal.FindStringSubmatch(strings.ReplaceAll(strings.TrimSpace(input), ",", ""))
+	if match == nil || match[1]+match[2] == "" {
+		return nil, false
+	}
+	intDigits, fracDigits := match[1], match[2]
+	operands := &pluralOperands{}
+	if match[3] != "" {
+		exp, err := strconv.Atoi(match[3])
+		if err != nil {
+			return nil, false
+		}
+		operands.e = uint64(exp)
+		// shift the decimal point to the right
+		if exp > len(fracDigits) {
+			fracDigits += strings.Repeat("0", exp-len(fracDigits))
+		}
+		intDigits, fracDigits = intDigits+fracDigits[:exp], fracDigits[exp:]
+	}
+	intDigits = strings.TrimLeft(intDigits, "0")
+	if len(intDigits) > maxPluralDigits {
+		operands.big = true
+		intDigits = intDigits[len(intDigits)-maxPluralDigits:]
+	}
+	operands.i = parsePluralDigits(intDigits)
+	operands.v = uint64(len(fracDigits))
+	operands.f = parsePluralDigits(fracDigits)
+	fracDigits = strings.TrimRight(fracDigits, "0")
+	operands.w = uint64(len(fracDigits))
+	operands.t = parsePluralDigits(fracDigits)
+	return operands, true
+}
+
+// parsePluralDigits parses a string of digits, keeping only the last maxPluralDigits digits.
+func parsePluralDigits(digits string) uint64 {
+	if len(digits) > maxPluralDigits {
+		digits = digits[len(digits)-maxPluralDigits:]
+	}
+	value, _ := strconv.ParseUint("0"+digits, 10, 64)
+	return value
+}
+
+// value returns the value of an operand, and whether the value is an integer.
+func (o *pluralOperands) value(operand byte) (uint64, bool) {
+	switch operand {
+	case 'n':
+		return o.i, o.t == 0
+	case 'i':
+		return o.i, true
+	case 'v':
+		return o.v, true
+	case 'w':
+		return o.w, true
+	case 'f':
+		return o.f, true
+	case 't':
+		return o.t, true
+	case 'c', 'e':
+		return o.e, true
+	}
+	return 0, false
+}
+
+// pluralRelation is a relation of a CLDR plural rule's condition, e.g. "n % 10 = 2..4".
+type pluralRelation struct {
+	operand byte        // one of n, i, v, w, f, t, c, e
+	mod     uint64      // the modulus, 0 if the relation has no modulus
+	negate  bool        // true if the relation is "!=", false if "="
+	ranges  [][2]uint64 // list of value ranges, a single value is stored as a range of itself
+}
+
+func (r *pluralRelation) match(o *pluralOperands) bool {
+	value, exact := o.value(r.operand)
+	if r.mod > 0 {
+		value %= r.mod
+	} else if o.big && (r.operand == 'n' || r.operand == 'i') {
+		// value is too big to be in any range
+		exact = false
+	}
+	found := false
+	for _, rng := range r.ranges {
+		if exact && value >= rng[0] && value <= rng[1] {
+			found = true
+			break
+		}
+	}
+	return found != r.negate
+}
+
+// pluralRule is a CLDR plural rule: a plural category and the condition to select it.
+//
+// The condition is stored in disjunctive normal form, e.g. "A and B or C" is stored as [[A, B], [C]].
+type pluralRule struct {
+	category  string
+	condition [][]pluralRelation
+}
+
+func (r *pluralRule) match(o *pluralOperands) bool {
+	for _, and := range r.condition {
+		matched := true
+		for i := range and {
+			if !and[i].match(o) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// pluralRules is the set of CLDR plural rules of a locale.
+type pluralRules struct {
+	rules []pluralRule
+}
+
+// category returns the plural category of the number represented by the plural operands.
+func (r *pluralRules) category(o *pluralOperands) string {
+	if r != nil && o != nil {
+		for i := range r.rules {
+			if r.rules[i].match(o) {
+				return r.rules[i].category
+			}
+		}
+	}
+	return pluralOther
+}
+
+var rePluralRelation = regexp.MustCompile(`^([nivwftce])\s*(?:%\s*(\d+))?\s*(!?=)\s*([\d.,]+)$`)
+
+func parsePluralRelation(input string) (pluralRelation, error) {
+	match := rePluralRelation.FindStringSubmatch(strings.TrimSpace(input))
+	if match == nil {
+		return pluralRelation{}, fmt.Errorf("invalid plural relation '%s'", input)
+	}
+	relation := pluralRelation{operand: match[1][0], negate: match[3] == "!="}
+	if match[2] != "" {
+		relation.mod, _ = strconv.ParseUint(match[2], 10, 64)
+	}
+	for _, rangeStr := range strings.Split(match[4], ",") {
+		tokens := strings.SplitN(rangeStr, "..", 2)
+		lower, err := strconv.ParseUint(tokens[0], 10, 64)
+		if err != nil {
+			return pluralRelation{}, fmt.Errorf("invalid plural relation '%s'", input)
+		}
+		upper := lower
+		if len(tokens) > 1 {
+			if upper, err = strconv.ParseUint(tokens[1], 10, 64); err != nil {
+				return pluralRelation{}, fmt.Errorf("invalid plural relation '%s'", input)
+			}
+		}
+		relation.ranges = append(relation.ranges, [2]uint64{lower, upper})
+	}
+	return relation, nil
+}
+
+// parsePluralRule parses a rule in format "<category>: <condition>", e.g. "one: i = 1 and v = 0".
+func parsePluralRule(input string) (pluralRule, error) {
+	tokens := strings.SplitN(input, ":", 2)
+	if len(tokens) != 2 {
+		return pluralRule{}, fmt.Errorf("invalid plural rule '%s'", input)
+	}
+	rule := pluralRule{category: strings.TrimSpace(tokens[0])}
+	for _, orStr := range strings.Split(tokens[1], " or ") {
+		var and []pluralRelation
+		for _, andStr := range strings.Split(orStr, " and ") {
+			relation, err := parsePluralRelation(andStr)
+			if err != nil {
+				return pluralRule{}, fmt.Errorf("invalid plural rule '%s': %s", input, err)
+			}
+			and = append(and, relation)
+		}
+		rule.condition = append(rule.condition, and)
+	}
+	return rule, nil
+}
+
+// pluralRulesData maps a space-separated list of locales to their plural rules.
+type pluralRulesData struct {
+	locales string
+	rules   []string
+}
+
+func buildPluralRulesTable(data []pluralRulesData) map[string]*pluralRules {
+	table := make(map[string]*pluralRules)
+	for _, entry := range data {
+		rules := &pluralRules{}
+		for _, ruleStr := range entry.rules {
+			rule, err := parsePluralRule(ruleStr)
+			if err != nil {
+				panic(err)
+			}
+			rules.rules = append(rules.rules, rule)
+		}
+		for _, locale := range strings.Fields(entry.locales) {
+			table[normalizePluralLocale(locale)] = rules
+		}
+	}
+	return table
+}
+
+var (
+	cardinalRulesTable = buildPluralRulesTable(cldrCardinalRules)
+	ordinalRulesTable  = buildPluralRulesTable(cldrOrdinalRules)
+)
+
+func normalizePluralLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// lookupPluralRules finds the plural rules of a locale in a rules table.
+//
+// Subtags are removed from the end of the locale until a match is found (e.g. "pt-PT-u-nu-latn" → "pt-PT-u-nu" → ... → "pt-PT").
+// If there is no match, rules of the "root" locale are returned.
+func lookupPluralRules(table map[string]*pluralRules, locale string) *pluralRules {
+	for key := normalizePluralLocale(locale); key != ""; {
+		if rules, ok := table[key]; ok {
+			return rules
+		}
+		pos := strings.LastIndex(key, "-")
+		if pos < 0 {
+			break
+		}
+		key = key[:pos]
+	}
+	return table["root"]
+}
+
+// pluralSelector picks plural forms of messages for a locale.
+type pluralSelector struct {
+	// locale is the locale of the messages, also used to format numbers of messages in ICU MessageFormat.
+	locale string
+
+	// legacy, if true, picks plural forms using the fixed count-to-form mapping of goyai v0.2.x.
+	legacy bool
+
+	// cardinal is the set of CLDR cardinal plural rules of the locale.
+	cardinal *pluralRules
+
+	// ordinal is the set of CLDR ordinal plural rules of the locale.
+	ordinal *pluralRules
+}
+
+func newPluralSelector(locale string, legacy bool) *pluralSelector {
+	return &pluralSelector{
+		locale:   locale,
+		legacy:   legacy,
+		cardinal: lookupPluralRules(cardinalRulesTable, locale),
+		ordinal:  lookupPluralRules(ordinalRulesTable, locale),
+	}
+}
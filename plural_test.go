@@ -0,0 +1,152 @@
+package goyai
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParsePluralRule(t *testing.T) {
+	testName := "TestParsePluralRule"
+	rule, err := parsePluralRule("few: v = 0 and i % 10 = 2..4 and i % 100 != 12..14 or f % 10 = 2,3,4")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "few", rule.category; v != e {
+		t.Fatalf("%s failed, expected category [%s] but received [%s]", testName, e, v)
+	}
+	if e, v := 2, len(rule.condition); v != e {
+		t.Fatalf("%s failed, expected %d and-conditions but received %d", testName, e, v)
+	}
+	if e, v := 3, len(rule.condition[0]); v != e {
+		t.Fatalf("%s failed, expected %d relations but received %d", testName, e, v)
+	}
+	if r := rule.condition[0][2]; r.operand != 'i' || r.mod != 100 || !r.negate || len(r.ranges) != 1 || r.ranges[0] != [2]uint64{12, 14} {
+		t.Fatalf("%s failed, invalid relation %#v", testName, r)
+	}
+	if r := rule.condition[1][0]; r.operand != 'f' || r.mod != 10 || r.negate || len(r.ranges) != 3 {
+		t.Fatalf("%s failed, invalid relation %#v", testName, r)
+	}
+}
+
+func TestParsePluralRule_Invalid(t *testing.T) {
+	testName := "TestParsePluralRule_Invalid"
+	inputs := []string{"i = 1", "one: x = 1", "one: i = a", "one: i = 1..b", "one: i % = 1", "one: i == 1"}
+	for _, input := range inputs {
+		if _, err := parsePluralRule(input); err == nil {
+			t.Fatalf("%s failed: expected error for input [%s]", testName, input)
+		}
+	}
+}
+
+func TestLookupPluralRules(t *testing.T) {
+	testName := "TestLookupPluralRules"
+	testCases := map[string]string{"en": "en", "EN_us": "en", "pt-PT": "pt_PT", "pt-BR": "pt", "sr-Latn-RS": "sr", "not-exist": "root", "": "root"}
+	for locale, expected := range testCases {
+		if e, v := cardinalRulesTable[normalizePluralLocale(expected)], lookupPluralRules(cardinalRulesTable, locale); v != e {
+			t.Fatalf("%s failed: locale [%s] should use rules of [%s]", testName, locale, expected)
+		}
+	}
+}
+
+func TestPluralRules_Cardinal(t *testing.T) {
+	testName := "TestPluralRules_Cardinal"
+	testCases := map[string]map[int]string{
+		"en": {0: "other", 1: "one", 2: "other", 5: "other", 21: "other"},
+		"vi": {0: "other", 1: "other", 2: "other"},
+		"fr": {0: "one", 1: "one", 2: "other", 1000000: "many"},
+		"ru": {0: "many", 1: "one", 2: "few", 4: "few", 5: "many", 11: "many", 12: "many", 21: "one", 22: "few", 111: "many"},
+		"pl": {1: "one", 2: "few", 5: "many", 12: "many", 22: "few", 101: "many"},
+		"ar": {0: "zero", 1: "one", 2: "two", 3: "few", 10: "few", 11: "many", 99: "many", 100: "other", 103: "few"},
+		"cy": {0: "zero", 1: "one", 2: "two", 3: "few", 4: "other", 6: "many", 7: "other"},
+		"gd": {1: "one", 11: "one", 2: "two", 12: "two", 3: "few", 19: "few", 20: "other"},
+		"lv": {0: "zero", 1: "one", 11: "zero", 21: "one", 2: "other"},
+	}
+	for locale, expected := range testCases {
+		rules := lookupPluralRules(cardinalRulesTable, locale)
+		for n, e := range expected {
+			operands, _ := newPluralOperands(n)
+			if v := rules.category(operands); v != e {
+				t.Fatalf("%s failed: locale [%s] / number %d / expected [%s] but received [%s]", testName, locale, n, e, v)
+			}
+		}
+	}
+}
+
+func TestNewPluralOperands(t *testing.T) {
+	testName := "TestNewPluralOperands"
+	testCases := []struct {
+		count    interface{}
+		expected pluralOperands
+	}{
+		{-12, pluralOperands{i: 12}},
+		{uint8(7), pluralOperands{i: 7}},
+		{1.5, pluralOperands{i: 1, v: 1, w: 1, f: 5, t: 5}},
+		{float32(0.25), pluralOperands{i: 0, v: 2, w: 2, f: 25, t: 25}},
+		{1.0, pluralOperands{i: 1}},
+		{"1.0", pluralOperands{i: 1, v: 1, w: 0, f: 0, t: 0}},
+		{"1.50", pluralOperands{i: 1, v: 2, w: 1, f: 50, t: 5}},
+		{" -1,000 ", pluralOperands{i: 1000}},
+		{"1.2c3", pluralOperands{i: 1200, e: 3}},
+		{"1.2345e2", pluralOperands{i: 123, v: 2, w: 2, f: 45, t: 45, e: 2}},
+		{".5", pluralOperands{v: 1, w: 1, f: 5, t: 5}},
+		{json.Number("2.10"), pluralOperands{i: 2, v: 2, w: 1, f: 10, t: 1}},
+		{big.NewInt(101), pluralOperands{i: 101}},
+		{new(big.Float).SetFloat64(2.5), pluralOperands{i: 2, v: 1, w: 1, f: 5, t: 5}},
+		{"123456789012345678901", pluralOperands{i: 456789012345678901, big: true}},
+		{true, pluralOperands{i: 1}},
+	}
+	for _, testCase := range testCases {
+		operands, ok := newPluralOperands(testCase.count)
+		if !ok || *operands != testCase.expected {
+			t.Fatalf("%s failed (%#v), expected %#v but received %#v", testName, testCase.count, testCase.expected, operands)
+		}
+	}
+
+	for _, count := range []interface{}{"not a number", "", ".", "1.2.3", math.NaN(), math.Inf(1), (*big.Int)(nil), []int{1}} {
+		if operands, ok := newPluralOperands(count); ok {
+			t.Fatalf("%s failed (%#v), expected invalid operands but received %#v", testName, count, operands)
+		}
+	}
+}
+
+func TestPluralRules_Decimal(t *testing.T) {
+	testName := "TestPluralRules_Decimal"
+	testCases := map[string]map[interface{}]string{
+		"en": {1.5: "other", "1.0": "other", "1": "one", "1,000": "other", 1.0: "one"},
+		"fr": {1.5: "one", "2.0": "other", "1000000": "many", "1c6": "many", "1.2c3": "other"},
+		"ru": {"1.5": "other", "21": "one", "1,002": "few"},
+		"lt": {0.5: "many", 1: "one"},
+		"ar": {"100000000000000000003": "few", "100000000000000000000": "other"},
+	}
+	for locale, expected := range testCases {
+		rules := lookupPluralRules(cardinalRulesTable, locale)
+		for n, e := range expected {
+			operands, _ := newPluralOperands(n)
+			if v := rules.category(operands); v != e {
+				t.Fatalf("%s failed: locale [%s] / number %v / expected [%s] but received [%s]", testName, locale, n, e, v)
+			}
+		}
+	}
+}
+
+func TestPluralRules_Ordinal(t *testing.T) {
+	testName := "TestPluralRules_Ordinal"
+	testCases := map[string]map[int]string{
+		"en": {0: "other", 1: "one", 2: "two", 3: "few", 4: "other", 11: "other", 12: "other", 13: "other", 21: "one", 22: "two", 23: "few", 101: "one"},
+		"vi": {1: "one", 2: "other"},
+		"ru": {1: "other", 2: "other"},
+		"it": {8: "many", 11: "many", 80: "many", 800: "many", 1: "other"},
+		"cy": {0: "zero", 1: "one", 2: "two", 3: "few", 5: "many", 7: "zero", 10: "other"},
+	}
+	for locale, expected := range testCases {
+		rules := lookupPluralRules(ordinalRulesTable, locale)
+		for n, e := range expected {
+			operands, _ := newPluralOperands(n)
+			if v := rules.category(operands); v != e {
+				t.Fatalf("%s failed: locale [%s] / number %d / expected [%s] but received [%s]", testName, locale, n, e, v)
+			}
+		}
+	}
+}
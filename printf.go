@@ -0,0 +1,75 @@
+package goyai
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// reAndroidFormatSpec matches format specifiers of java.util.Formatter used by Android string resources
+	// (e.g. "%s", "%1$d", "%.2f", "%%").
+	reAndroidFormatSpec = regexp.MustCompile(`%(?:(\d+)\$)?[-#+0,(]*\d*(?:\.\d+)?([sSdfxXoeEgGcCbBhH%])`)
+
+	// reAppleFormatSpec matches format specifiers of Foundation's String Format Specifiers used by Apple .strings and
+	// .stringsdict files (e.g. "%@", "%1$@", "%ld", "%.2f", "%%").
+	reAppleFormatSpec = regexp.MustCompile(`%(?:(\d+)\$)?[-#+0']*\d*(?:\.\d+)?(?:hh|h|ll|l|q|z|t|j|L)?([@dDiuUxXoOfFeEgGcCsSpaA%])`)
+
+	// reCFormatSpec matches format specifiers of C printf used by gettext entries with flag "c-format" (e.g. "%s",
+	// "%1$d", "%ld", "%.2f", "%%").
+	reCFormatSpec = regexp.MustCompile(`%(?:(\d+)\$)?[-#+ 0']*\d*(?:\.\d+)?(?:hh|h|ll|l|L|q|j|z|t)?([diouxXeEfFgGaAcsp%])`)
+)
+
+// convertPrintfFormat converts printf-style format specifiers of a string (e.g. "Hello %1$s, you have %2$d messages")
+// to positional placeholders (e.g. "Hello {{._0}}, you have {{._1}} messages"). Specifiers without explicit argument
+// index take the next argument, "%%" is a literal "%". Strings without format specifiers (other than "%%") are returned
+// as-is, as they are not formatted by the platforms.
+func convertPrintfFormat(s string, reSpec *regexp.Regexp) string {
+	formatted := false
+	for _, match := range reSpec.FindAllStringSubmatch(s, -1) {
+		if match[2] != "%" {
+			formatted = true
+			break
+		}
+	}
+	if !formatted {
+		return s
+	}
+	next := 0
+	return reSpec.ReplaceAllStringFunc(s, func(spec string) string {
+		match := reSpec.FindStringSubmatch(spec)
+		if match[2] == "%" {
+			return "%"
+		}
+		pos := next
+		if match[1] != "" {
+			pos, _ = strconv.Atoi(match[1])
+			pos--
+		} else {
+			next++
+		}
+		return "{{._" + strconv.Itoa(pos) + "}}"
+	})
+}
+
+// templateToPrintf converts placeholders of a message template to printf-style format specifiers with explicit
+// argument index and the specified conversion (e.g. "Hello {{.name}}, {{._1}}" → "Hello %1$s, %2$s"), following the
+// mapping of placeholders to params of Localize (see LocalizeConfig.TemplateData). Argument indexes are shifted by
+// offset, e.g. past the plural count argument of .stringsdict formats. Literal "%" are escaped as "%%" if the template
+// has placeholders.
+func templateToPrintf(tpl, conversion string, offset int) string {
+	forwardMap, _ := placeholderPositions(tpl)
+	if len(forwardMap) == 0 {
+		return strings.ReplaceAll(tpl, templateLiteralBrace, "{")
+	}
+	literal := strings.NewReplacer(templateLiteralBrace, "{", "%", "%%")
+	var sb strings.Builder
+	last := 0
+	for _, loc := range rePlaceholderToken.FindAllStringSubmatchIndex(tpl, -1) {
+		sb.WriteString(literal.Replace(tpl[last:loc[0]]))
+		sb.WriteString("%" + strconv.Itoa(forwardMap[tpl[loc[2]:loc[3]]]+1+offset) + "$" + conversion)
+		last = loc[1]
+	}
+	sb.WriteString(literal.Replace(tpl[last:]))
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package goyai
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestConvertPrintfFormat(t *testing.T) {
+	testName := "TestConvertPrintfFormat"
+	testCases := []struct {
+		input    string
+		reSpec   *regexp.Regexp
+		expected string
+	}{
+		{"Hello %s", reAndroidFormatSpec, "Hello {{._0}}"},
+		{"%2$s has %1$d files", reAndroidFormatSpec, "{{._1}} has {{._0}} files"},
+		{"%s: %.2f%%", reAndroidFormatSpec, "{{._0}}: {{._1}}%"},
+		{"50% off", reAndroidFormatSpec, "50% off"},
+		{"100%% sure", reAndroidFormatSpec, "100%% sure"},
+		{"Hello %@", reAppleFormatSpec, "Hello {{._0}}"},
+		{"%2$@ has %1$ld files", reAppleFormatSpec, "{{._1}} has {{._0}} files"},
+		{"%d%%", reAppleFormatSpec, "{{._0}}%"},
+		{"%s has %lu files", reCFormatSpec, "{{._0}} has {{._1}} files"},
+		{"%2$s: %1$'d%%", reCFormatSpec, "{{._1}}: {{._0}}%"},
+	}
+	for _, tc := range testCases {
+		if v := convertPrintfFormat(tc.input, tc.reSpec); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.input, tc.expected, v)
+		}
+	}
+}
+
+func TestTemplateToPrintf(t *testing.T) {
+	testName := "TestTemplateToPrintf"
+	testCases := []struct {
+		input    string
+		offset   int
+		expected string
+	}{
+		{"Hello", 0, "Hello"},
+		{"100% sure", 0, "100% sure"},
+		{"Hello {{.name}}", 0, "Hello %1$s"},
+		{"{{._1}} has {{._0}} files", 0, "%2$s has %1$s files"},
+		{"{{.name}} {{._0}}: {{.n}}%", 0, "%2$s %1$s: %3$s%%"},
+		{`{{"{"}}{{"{"}}.x}}`, 0, "{{.x}}"},
+		{`{{"{"}}{{.name}}}`, 0, "{%1$s}"},
+		{"{{._1}} has {{._0}} files", 1, "%3$s has %2$s files"},
+	}
+	for _, tc := range testCases {
+		if v := templateToPrintf(tc.input, "s", tc.offset); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.input, tc.expected, v)
+		}
+	}
+}
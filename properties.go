@@ -0,0 +1,202 @@
+package goyai
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// propertiesLocale infers the locale of a Java .properties file from the suffix of its file name, following the
+// ResourceBundle naming convention "<base-name>_<language>[_<country>[_<variant>]].properties" (e.g.
+// "messages_vi.properties", "messages_pt_BR.properties"). It returns false if the file name has no locale suffix
+// (e.g. "messages.properties", the base bundle).
+//
+// The base name may contain underscores (e.g. "my_app_vi.properties"), so the locale suffix is the shortest one, of up
+// to 3 segments from the right, whose first segment is a known language in lower case (e.g. "vi", not "app_vi" or "VI").
+func propertiesLocale(relPath string) (string, bool) {
+	name := path.Base(relPath)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	segments := strings.Split(name, "_")
+	for n := 1; n <= 3 && n < len(segments); n++ {
+		suffix := segments[len(segments)-n:]
+		if suffix[0] != strings.ToLower(suffix[0]) || !isKnownLanguage(suffix[0]) {
+			continue
+		}
+		if locale := strings.Join(suffix, "_"); isFileNameLocale(locale) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// parseProperties parses the content of a Java .properties file into key/value pairs, following the syntax of
+// java.util.Properties: "#" and "!" comment lines, "=", ":" or whitespace key/value separators, line continuations with
+// a trailing backslash, and escape sequences (including "\uXXXX"). The content is read as UTF-8.
+func parseProperties(buf []byte) (map[string]string, error) {
+	result := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(strings.TrimPrefix(string(buf), "\xef\xbb\xbf"), "\r\n", "\n"), "\n")
+	for lineNum := 0; lineNum < len(lines); lineNum++ {
+		line := strings.TrimLeft(lines[lineNum], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		startLine := lineNum + 1
+		// join continuation lines: a line ends with an odd number of backslashes
+		for isPropertiesContinued(line) && lineNum+1 < len(lines) {
+			lineNum++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[lineNum], " \t\f")
+		}
+		if isPropertiesContinued(line) {
+			line = line[:len(line)-1]
+		}
+
+		// key ends at the first unescaped separator
+		keyEnd := len(line)
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if line[i] == '=' || line[i] == ':' || line[i] == ' ' || line[i] == '\t' || line[i] == '\f' {
+				keyEnd = i
+				break
+			}
+		}
+		value := strings.TrimLeft(line[keyEnd:], " \t\f")
+		if value != "" && (value[0] == '=' || value[0] == ':') {
+			value = strings.TrimLeft(value[1:], " \t\f")
+		}
+		key, err := unescapeProperties(line[:keyEnd])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing properties line %d: %w", startLine, err)
+		}
+		if value, err = unescapeProperties(value); err != nil {
+			return nil, fmt.Errorf("error parsing properties line %d: %w", startLine, err)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func isPropertiesContinued(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func unescapeProperties(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX escape")
+			}
+			i += 4
+			// surrogate pairs are encoded as two consecutive escapes
+			if r >= 0xD800 && r < 0xDC00 && i+7 <= len(s) && s[i+1:i+3] == `\u` {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 16); err == nil && low >= 0xDC00 && low < 0xE000 {
+					r = 0x10000 + (r-0xD800)<<10 + (low - 0xDC00)
+					i += 6
+				}
+			}
+			sb.WriteRune(rune(r))
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+var reMessageFormatArg = regexp.MustCompile(`{\s*\d+\s*(,[^{}]*)?}`)
+
+// convertMessageFormat converts a Java MessageFormat pattern (e.g. "Hello {0}, you have {1,number} messages") to a
+// template with positional placeholders (e.g. "Hello {{._0}}, you have {{._1}} messages"). Format types and styles of
+// arguments are ignored, quotes are processed as in MessageFormat (two consecutive single quotes are a single quote, and
+// text between single quotes is literal).
+//
+// Patterns without arguments are kept as-is (only "{" are escaped to be literal in templates), as ResourceBundle users
+// only apply MessageFormat to messages with arguments.
+func convertMessageFormat(pattern string) string {
+	if !reMessageFormatArg.MatchString(pattern) {
+		return escapeTemplateBraces(pattern)
+	}
+	var sb strings.Builder
+	quoted := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i++
+			} else {
+				quoted = !quoted
+			}
+		case c == '{' && quoted:
+			sb.WriteString(templateLiteralBrace)
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				sb.WriteString(templateLiteralBrace)
+				continue
+			}
+			arg := strings.TrimSpace(strings.SplitN(pattern[i+1:i+end], ",", 2)[0])
+			if _, err := strconv.Atoi(arg); err != nil {
+				sb.WriteString(templateLiteralBrace)
+				continue
+			}
+			sb.WriteString("{{._" + arg + "}}")
+			i += end
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// loadLangFileProperties loads messages of a locale from a Java .properties file. Each key is a message id, MessageFormat
+// arguments "{0}", "{1}"... of values are converted to positional placeholders (see LocalizeConfig.TemplateData).
+func loadLangFileProperties(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	if locale == "" {
+		return fmt.Errorf("error parsing properties file: locale is not specified")
+	}
+	properties, err := parseProperties(buf)
+	if err != nil {
+		return err
+	}
+	msgMap := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		if key == "_display" || key == "_name" {
+			msgMap[key] = value
+			continue
+		}
+		// a map, rather than a string, preserves leading/trailing whitespaces of the value
+		msgMap[key] = map[string]string{pluralOther: convertMessageFormat(value)}
+	}
+	return parseLangData(localesStore, messagesStore, map[string]map[string]interface{}{locale: msgMap})
+}
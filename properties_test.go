@@ -0,0 +1,163 @@
+package goyai
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseProperties(t *testing.T) {
+	testName := "TestParseProperties"
+	content := "# comment\n" +
+		"! another comment\n" +
+		"hello = Hello, world\n" +
+		"hello_who:Hello {0}\n" +
+		"bye Goodbye   \n" +
+		"   indented=yes\n" +
+		"multiline = first, \\\n" +
+		"            second, \\\n" +
+		"            third\n" +
+		"escaped\\ key\\=x = tab\\there\\nnewline\n" +
+		"unicode = Xin ch\\u00e0o \\ud83d\\ude00\n" +
+		"backslash = C:\\\\temp\\\\\n" +
+		"empty\n" +
+		"empty2 =\n"
+	properties, err := parseProperties([]byte(content))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	expected := map[string]string{
+		"hello":         "Hello, world",
+		"hello_who":     "Hello {0}",
+		"bye":           "Goodbye   ",
+		"indented":      "yes",
+		"multiline":     "first, second, third",
+		"escaped key=x": "tab\there\nnewline",
+		"unicode":       "Xin chào 😀",
+		"backslash":     `C:\temp\`,
+		"empty":         "",
+		"empty2":        "",
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, properties)
+	}
+	if _, err := parseProperties([]byte("key = \\u00zz")); err == nil {
+		t.Fatalf("%s failed: expected error for malformed \\uXXXX escape", testName)
+	}
+}
+
+func TestPropertiesLocale(t *testing.T) {
+	testName := "TestPropertiesLocale"
+	testCases := []struct {
+		relPath, locale string
+		ok              bool
+	}{
+		{"messages_vi.properties", "vi", true},
+		{"messages_pt_BR.properties", "pt_BR", true},
+		{"i18n/app_messages_en.properties", "en", true},
+		{"messages.properties", "", false},
+		{"app_messages.properties", "", false},
+		{"my_app_vi.properties", "vi", true},
+		{"my_app_pt_BR.properties", "pt_BR", true},
+		{"messages_en_US_POSIX.properties", "en_US_POSIX", true},
+		{"messages_de_vi.properties", "vi", true},
+		{"messages_VI.properties", "", false},
+		{"messages_xx.properties", "", false},
+	}
+	for _, tc := range testCases {
+		locale, ok := propertiesLocale(tc.relPath)
+		if ok != tc.ok || locale != tc.locale {
+			t.Fatalf("%s failed for [%s]: expected %#v/%v but received %#v/%v", testName, tc.relPath, tc.locale, tc.ok, locale, ok)
+		}
+	}
+}
+
+func TestConvertMessageFormat(t *testing.T) {
+	testName := "TestConvertMessageFormat"
+	testCases := []struct {
+		pattern, expected string
+	}{
+		{"Hello", "Hello"},
+		{"Don't worry", "Don't worry"},
+		{"Hello {0}", "Hello {{._0}}"},
+		{"{1} of {0,number,integer} files", "{{._1}} of {{._0}} files"},
+		{"Don''t delete {0}", "Don't delete {{._0}}"},
+		{"'{0}' is {0}", `{{"{"}}0} is {{._0}}`},
+		{"{name} is {0}", `{{"{"}}name} is {{._0}}`},
+		{"Type {{name}} here", `Type {{"{"}}{{"{"}}name}} here`},
+	}
+	for _, tc := range testCases {
+		if v := convertMessageFormat(tc.pattern); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.pattern, tc.expected, v)
+		}
+	}
+}
+
+func TestBuildI18n_Properties(t *testing.T) {
+	testName := "TestBuildI18n_Properties"
+	fsys := fstest.MapFS{
+		"i18n/messages.properties":       {Data: []byte("_name = English\nhello = Hello {0}\nfiles = {1} has {0} files\nquote = Don't {0}\nbraces = Type {{name}} here\n")},
+		"i18n/messages_vi.properties":    {Data: []byte("_name = Ti\\u1ebfng Vi\\u1ec7t\nhello = Xin ch\\u00e0o {0}\n")},
+		"i18n/messages_pt_BR.properties": {Data: []byte("files = {1} tem {0} arquivos\n")},
+	}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n", DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := 3, len(i18n.AvailableLocales()); v != e {
+		t.Fatalf("%s failed, expected %d available locales but received %d", testName, e, v)
+	}
+	if e, v := "Tiếng Việt", i18n.(*Goi18n).locales["vi"].DisplayName; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	testCases := []struct {
+		locale, msgId string
+		params        []interface{}
+		expected      string
+	}{
+		{"en", "hello", []interface{}{"Thanh"}, "Hello Thanh"},
+		{"vi", "hello", []interface{}{"Thanh"}, "Xin chào Thanh"},
+		{"en", "files", []interface{}{3, "Folder"}, "Folder has 3 files"},
+		{"pt-BR", "files", []interface{}{3, "Pasta"}, "Pasta tem 3 arquivos"},
+		{"vi", "files", []interface{}{3, "Folder"}, "Folder has 3 files"},
+		// as with Java MessageFormat, a single quote starts a quoted literal
+		{"en", "quote", []interface{}{"panic"}, "Dont {0}"},
+		// braces of patterns without arguments are literal
+		{"en", "braces", nil, "Type {{name}} here"},
+	}
+	for _, tc := range testCases {
+		if v := i18n.Localize(tc.locale, tc.msgId, tc.params...); v != tc.expected {
+			t.Fatalf("%s failed for [%s/%s]: expected %#v but received %#v", testName, tc.locale, tc.msgId, tc.expected, v)
+		}
+	}
+
+	if _, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n"}); err == nil {
+		t.Fatalf("%s failed: expected error for base bundle without default locale", testName)
+	}
+	i18n, err = BuildI18nFromBytes([]byte("hello = Hi {0}"), I18nOptions{I18nFileFormat: Properties, DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Hi there", i18n.Localize("en", "hello", "there"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestBuildTemplateData_Positional(t *testing.T) {
+	testName := "TestBuildTemplateData_Positional"
+	testCases := []struct {
+		msg      string
+		params   []interface{}
+		expected map[string]interface{}
+	}{
+		{"{{.a}} {{.b}} {{.a}}", []interface{}{1, 2}, map[string]interface{}{"a": 1, "b": 2}},
+		{"{{._1}}-{{._0}}", []interface{}{"a", "b"}, map[string]interface{}{"_0": "a", "_1": "b"}},
+		{"{{._2}}", []interface{}{"a", "b", "c"}, map[string]interface{}{"_2": "c"}},
+		{"{{.name}} {{._0}}", []interface{}{"a", "b"}, map[string]interface{}{"_0": "a", "name": "b"}},
+	}
+	for _, tc := range testCases {
+		if v := _buildTemplateData(tc.msg, tc.params...); !reflect.DeepEqual(v, tc.expected) {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.msg, tc.expected, v)
+		}
+	}
+}
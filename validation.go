@@ -0,0 +1,151 @@
+package goyai
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationProblem is a problem of a language file found while building an I18n instance.
+//
+// Available since v0.3.0
+type ValidationProblem struct {
+	// File is the path of the language file, empty if the content is not loaded from a file (e.g. BuildI18nFromBytes).
+	File string
+
+	// Locale is the locale of the problematic message, empty if the problem is not specific to a locale (e.g. the file
+	// cannot be parsed).
+	Locale string
+
+	// MessageId is the id of the problematic message, empty if the problem is not specific to a message.
+	MessageId string
+
+	// Problem describes the problem.
+	Problem string
+}
+
+// String implements fmt.Stringer.
+func (p ValidationProblem) String() string {
+	where := make([]string, 0, 3)
+	if p.File != "" {
+		where = append(where, "file ["+p.File+"]")
+	}
+	if p.Locale != "" {
+		where = append(where, "locale ["+p.Locale+"]")
+	}
+	if p.MessageId != "" {
+		where = append(where, "message ["+p.MessageId+"]")
+	}
+	if len(where) == 0 {
+		return p.Problem
+	}
+	return strings.Join(where, ", ") + ": " + p.Problem
+}
+
+// ValidationError lists problems of language files found while building an I18n instance. Messages that cannot be
+// parsed make BuildI18n return a ValidationError; in strict mode (see I18nOptions.Strict), all problems of all
+// language files are collected into a single ValidationError.
+//
+// Available since v0.3.0
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%d problems found in language files:", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add adds err as a problem; problems of a ValidationError are added with file filled in if not set.
+func (e *ValidationError) add(file, locale, msgId string, err error) {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		e.Problems = append(e.Problems, ValidationProblem{File: file, Locale: locale, MessageId: msgId, Problem: err.Error()})
+		return
+	}
+	for _, p := range validationErr.Problems {
+		if p.File == "" {
+			p.File = file
+		}
+		e.Problems = append(e.Problems, p)
+	}
+}
+
+// sort sorts problems by file, locale and message id.
+func (e *ValidationError) sort() {
+	sort.SliceStable(e.Problems, func(i, j int) bool {
+		x, y := e.Problems[i], e.Problems[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Locale != y.Locale {
+			return x.Locale < y.Locale
+		}
+		return x.MessageId < y.MessageId
+	})
+}
+
+// placeholders returns the placeholder tokens (e.g. "name", "_0") of all contents of the message and its variants.
+func (m *Message) placeholders() []string {
+	contents := []string{m.placeholderTemplate()}
+	if m.Syntax != SyntaxIcu {
+		contents = append(contents, m.Zero, m.One, m.Two, m.Few, m.Many)
+		for _, count := range m.exactCounts() {
+			contents = append(contents, m.Exact[count])
+		}
+	}
+	tokens := make([]string, 0)
+	for _, content := range contents {
+		for _, match := range rePlaceholderToken.FindAllStringSubmatch(m.defaultDelims(content), -1) {
+			if !containsString(tokens, match[1]) {
+				tokens = append(tokens, match[1])
+			}
+		}
+	}
+	for _, variant := range m.Variants {
+		for _, token := range variant.placeholders() {
+			if !containsString(tokens, token) {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// validatePlaceholders checks that messages reference only placeholders that are referenced by the same messages of
+// the default locale, which catches typos in translations (e.g. "{{.nmae}}").
+func validatePlaceholders(messagesStore map[string]map[string]*Message, defaultLocale string) []ValidationProblem {
+	problems := make([]ValidationProblem, 0)
+	references := messagesStore[defaultLocale]
+	if len(references) == 0 {
+		return problems
+	}
+	for locale, messages := range messagesStore {
+		if locale == defaultLocale {
+			continue
+		}
+		for msgId, msg := range messages {
+			reference := references[msgId]
+			if reference == nil {
+				continue
+			}
+			allowed := reference.placeholders()
+			for _, token := range msg.placeholders() {
+				if !containsString(allowed, token) {
+					problems = append(problems, ValidationProblem{File: msg.file, Locale: locale, MessageId: msgId,
+						Problem: fmt.Sprintf("placeholder [%s] is not referenced by the message of default locale [%s]", token, defaultLocale)})
+				}
+			}
+		}
+	}
+	return problems
+}
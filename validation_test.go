@@ -0,0 +1,126 @@
+package goyai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuildI18n_Strict(t *testing.T) {
+	testName := "TestBuildI18n_Strict"
+	fsys := fstest.MapFS{
+		"i18n/en.yaml": {Data: []byte(`
+en:
+  hello: "Hello {{.name}}"
+  files:
+    one: "{{.count}} file in {{.dir}}"
+    other: "{{.count}} files in {{.dir}}"
+  greeting:
+    other: "Hi {{.name}}"
+    select:
+      formal: "Good day {{.title}} {{.name}}"
+`)},
+		"i18n/vi.yaml": {Data: []byte(`
+vi:
+  hello: "Xin chào {{.nmae}}"
+  files:
+    other: "{{.count}} tập tin trong {{.dir}"
+  greeting:
+    other: "Chào {{.title}} {{.name}}"
+  count:
+    one: 1
+    other: "Nhiều"
+`)},
+		"i18n/ru.json": {Data: []byte(`{"ru": {"hello": "Привет {{.name}}"`)},
+		"i18n/de.yaml": {Data: []byte(`
+de:
+  _syntax: icu
+  hello: "Hallo {name}"
+  files: "{count, plural, one {# Datei} other {# Dateien in {folder}}}"
+`)},
+	}
+	expected := []ValidationProblem{
+		{File: "i18n/de.yaml", Locale: "de", MessageId: "files", Problem: "placeholder [folder] is not referenced by the message of default locale [en]"},
+		{File: "i18n/ru.json", Problem: "unexpected end of JSON input"},
+		{File: "i18n/vi.yaml", Locale: "vi", MessageId: "count", Problem: "error parsing message data at 'count.one'"},
+		{File: "i18n/vi.yaml", Locale: "vi", MessageId: "files"},
+		{File: "i18n/vi.yaml", Locale: "vi", MessageId: "hello", Problem: "placeholder [nmae] is not referenced by the message of default locale [en]"},
+	}
+
+	_, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n", DefaultLocale: "en", Strict: true})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("%s failed: expected ValidationError but received %#v", testName, err)
+	}
+	if len(validationErr.Problems) != len(expected) {
+		t.Fatalf("%s failed: expected %d problems but received %d\n%s", testName, len(expected), len(validationErr.Problems), err)
+	}
+	for i, e := range expected {
+		v := validationErr.Problems[i]
+		if v.File != e.File || v.Locale != e.Locale || v.MessageId != e.MessageId || !strings.Contains(v.Problem, e.Problem) {
+			t.Fatalf("%s failed: expected problem %#v but received %#v", testName, e, v)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "5 problems found in language files:") || !strings.Contains(err.Error(), "file [i18n/vi.yaml], locale [vi], message [hello]: placeholder [nmae]") {
+		t.Fatalf("%s failed: unexpected error message\n%s", testName, err)
+	}
+
+	// non-strict mode fails at the first problem
+	if _, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n", DefaultLocale: "en"}); err == nil {
+		t.Fatalf("%s failed: expected error in non-strict mode", testName)
+	}
+
+	// valid files, placeholders of the default locale's select variants are allowed
+	delete(fsys, "i18n/ru.json")
+	delete(fsys, "i18n/de.yaml")
+	fsys["i18n/vi.yaml"] = &fstest.MapFile{Data: []byte("vi:\n  hello: \"Xin chào {{.name}}\"\n  greeting: \"Chào {{.title}} {{.name}}\"\n  extra: \"{{.anything}}\"")}
+	i18n, err := BuildI18n(I18nOptions{FS: fsys, ConfigFileOrDir: "i18n", DefaultLocale: "en", Strict: true})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+}
+
+func TestBuildI18nFromBytes_Strict(t *testing.T) {
+	testName := "TestBuildI18nFromBytes_Strict"
+	content := `
+en:
+  hello: "Hello {{.name}}"
+  bye: "Bye {{.name"
+  count:
+    one: 1
+    other: "Items"
+`
+	_, err := BuildI18nFromBytes([]byte(content), I18nOptions{DefaultLocale: "en", Strict: true})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("%s failed: expected ValidationError but received %#v", testName, err)
+	}
+	if e, v := 2, len(validationErr.Problems); v != e {
+		t.Fatalf("%s failed: expected %d problems but received %d\n%s", testName, e, v, err)
+	}
+	if e, v := "bye", validationErr.Problems[0].MessageId; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+	if e, v := "count", validationErr.Problems[1].MessageId; v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	testName := "TestValidationError_Error"
+	testCases := []struct {
+		err      *ValidationError
+		expected string
+	}{
+		{&ValidationError{Problems: []ValidationProblem{{Problem: "oops"}}}, "oops"},
+		{&ValidationError{Problems: []ValidationProblem{{File: "en.yaml", Locale: "en", MessageId: "hello", Problem: "oops"}}}, "file [en.yaml], locale [en], message [hello]: oops"},
+		{&ValidationError{Problems: []ValidationProblem{{File: "en.yaml", Problem: "oops"}, {Locale: "vi", Problem: "oops"}}},
+			"2 problems found in language files:\n  - file [en.yaml]: oops\n  - locale [vi]: oops"},
+	}
+	for _, tc := range testCases {
+		if v := tc.err.Error(); v != tc.expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, tc.expected, v)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package goyai
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// XliffVersion is the version of XLIFF files written by function ExportXliff.
+//
+// Available since v0.3.0
+type XliffVersion string
+
+const (
+	// Xliff12 is XLIFF version 1.2.
+	Xliff12 XliffVersion = "1.2"
+
+	// Xliff20 is XLIFF version 2.0.
+	Xliff20 XliffVersion = "2.0"
+)
+
+type xliffDoc struct {
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"` // XLIFF 2.0
+	TrgLang string      `xml:"trgLang,attr"` // XLIFF 2.0
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	SourceLanguage string      `xml:"source-language,attr"` // XLIFF 1.2
+	TargetLanguage string      `xml:"target-language,attr"` // XLIFF 1.2
+	Body           *xliffGroup `xml:"body"`                 // XLIFF 1.2
+	xliffGroup                 // XLIFF 2.0: units and groups are direct children of file
+}
+
+type xliffNote struct {
+	Text string `xml:",chardata"`
+}
+
+type xliffGroup struct {
+	Id         string       `xml:"id,attr"`
+	Name       string       `xml:"name,attr"`    // XLIFF 2.0
+	ResName    string       `xml:"resname,attr"` // XLIFF 1.2
+	Notes      []xliffNote  `xml:"note"`         // XLIFF 1.2
+	Notes20    []xliffNote  `xml:"notes>note"`   // XLIFF 2.0
+	TransUnits []xliffUnit  `xml:"trans-unit"`   // XLIFF 1.2
+	Units      []xliffUnit  `xml:"unit"`         // XLIFF 2.0
+	Groups     []xliffGroup `xml:"group"`
+}
+
+// xliffText is the text of a source or target element, including texts of inline elements.
+type xliffText string
+
+// UnmarshalXML implements xml.Unmarshaler.
+//
+// Texts of inline elements wrapping content (e.g. <g>, <mrk>, <pc>) are kept. Placeholder elements <x> and <ph> are
+// replaced with their text equivalents (attribute "equiv-text" of XLIFF 1.2, "equiv" of XLIFF 2.0), which are usually
+// placeholders of the original messages (e.g. <x id="1" equiv-text="{{.name}}"/>); placeholder elements without text
+// equivalent are errors. Native codes and other empty inline elements (e.g. <bpt>, <ept>, <bx/>, <sc/>) are ignored.
+func (t *xliffText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var sb strings.Builder
+	depth, skipDepth := 0, 0 // depth of inline elements, and depth of the inline element whose content is skipped
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.CharData:
+			if skipDepth == 0 {
+				sb.Write(token)
+			}
+		case xml.StartElement:
+			depth++
+			if skipDepth > 0 {
+				continue
+			}
+			switch token.Name.Local {
+			case "x", "ph":
+				equiv := xmlAttr(token, "equiv-text")
+				if equiv == "" {
+					equiv = xmlAttr(token, "equiv")
+				}
+				if equiv == "" {
+					return fmt.Errorf("inline element <%s id=\"%s\"> of <%s> has no text equivalent", token.Name.Local, xmlAttr(token, "id"), start.Name.Local)
+				}
+				sb.WriteString(equiv)
+				skipDepth = depth
+			case "bpt", "ept", "it", "bx", "ex", "sc", "ec", "sm", "em":
+				skipDepth = depth
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				*t = xliffText(sb.String())
+				return nil
+			}
+			if depth == skipDepth {
+				skipDepth = 0
+			}
+			depth--
+		}
+	}
+}
+
+// xmlAttr returns the value of an attribute of an element, or empty string if the element does not have the attribute.
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+type xliffSegment struct {
+	Source xliffText `xml:"source"`
+	Target xliffText `xml:"target"`
+}
+
+type xliffUnit struct {
+	Id       string         `xml:"id,attr"`
+	Name     string         `xml:"name,attr"`    // XLIFF 2.0
+	ResName  string         `xml:"resname,attr"` // XLIFF 1.2
+	Notes    []xliffNote    `xml:"note"`         // XLIFF 1.2
+	Notes20  []xliffNote    `xml:"notes>note"`   // XLIFF 2.0
+	Source   xliffText      `xml:"source"`       // XLIFF 1.2
+	Target   *xliffText     `xml:"target"`       // XLIFF 1.2
+	Segments []xliffSegment `xml:"segment"`      // XLIFF 2.0
+}
+
+// xliffKey returns the message id of a unit or group: its name (XLIFF 2.0) or resname (XLIFF 1.2) if specified, otherwise
+// its id.
+func xliffKey(id, name, resName string) string {
+	if name != "" {
+		return name
+	}
+	if resName != "" {
+		return resName
+	}
+	return id
+}
+
+func xliffNotes(notes ...[]xliffNote) string {
+	lines := make([]string, 0)
+	for _, list := range notes {
+		for _, note := range list {
+			if text := strings.TrimSpace(note.Text); text != "" {
+				lines = append(lines, text)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var reXliffPluralKey = regexp.MustCompile(`^(.+)\[(zero|one|two|few|many|other)\]$`)
+
+// loadLangFileXliff loads messages from a XLIFF 1.2 or 2.0 file.
+//
+// Messages are loaded from translations (target) for the target language of the file, or from source texts for the
+// source language if the file has no target language. If locale is not empty, it overrides the target language. See
+// xliffText for how inline elements are loaded.
+func loadLangFileXliff(localesStore map[string]*LocaleInfo, messagesStore map[string]map[string]*Message, buf []byte, locale string) error {
+	var doc xliffDoc
+	if err := xml.Unmarshal(buf, &doc); err != nil {
+		return fmt.Errorf("error parsing XLIFF file: %w", err)
+	}
+	langData := make(map[string]map[string]interface{})
+	for _, file := range doc.Files {
+		// languages are attributes of <file> in XLIFF 1.2, and of <xliff> in XLIFF 2.x
+		sourceLang, targetLang := file.SourceLanguage, file.TargetLanguage
+		if strings.HasPrefix(doc.Version, "2.") {
+			sourceLang, targetLang = doc.SrcLang, doc.TrgLang
+		}
+		if sourceLang == "" && targetLang == "" {
+			return fmt.Errorf("error parsing XLIFF %s file: missing target and source language", doc.Version)
+		}
+		fileLocale, useSource := locale, false
+		if fileLocale == "" {
+			fileLocale = targetLang
+		}
+		if fileLocale == "" {
+			fileLocale, useSource = sourceLang, true
+		}
+		msgMap := make(map[string]map[string]string)
+		descriptions := make(map[string]string)
+		group := file.xliffGroup
+		if file.Body != nil {
+			group = *file.Body
+		}
+		collectXliffGroup(&group, useSource, msgMap, descriptions)
+		if langData[fileLocale] == nil {
+			langData[fileLocale] = make(map[string]interface{})
+		}
+		for msgId, msgData := range msgMap {
+			if msgData[pluralOther] == "" {
+				continue
+			}
+			if desc := descriptions[msgId]; desc != "" {
+				msgData["description"] = desc
+			}
+			langData[fileLocale][msgId] = msgData
+		}
+	}
+	return parseLangData(localesStore, messagesStore, langData)
+}
+
+// collectXliffGroup collects texts of units (plural variants "<msg-id>[<plural-form>]" are merged into one message)
+// and notes of units and groups of a group, recursively.
+func collectXliffGroup(group *xliffGroup, useSource bool, msgMap map[string]map[string]string, descriptions map[string]string) {
+	if desc := xliffNotes(group.Notes, group.Notes20); desc != "" {
+		descriptions[xliffKey(group.Id, group.Name, group.ResName)] = desc
+	}
+	for _, units := range [][]xliffUnit{group.TransUnits, group.Units} {
+		for _, unit := range units {
+			msgId, form := xliffKey(unit.Id, unit.Name, unit.ResName), pluralOther
+			if match := reXliffPluralKey.FindStringSubmatch(msgId); match != nil {
+				msgId, form = match[1], match[2]
+			} else if desc := xliffNotes(unit.Notes, unit.Notes20); desc != "" {
+				descriptions[msgId] = desc
+			}
+			text := string(unit.Source)
+			if !useSource {
+				text = ""
+				if unit.Target != nil {
+					text = string(*unit.Target)
+				}
+			}
+			for _, segment := range unit.Segments {
+				if useSource {
+					text += string(segment.Source)
+				} else {
+					text += string(segment.Target)
+				}
+			}
+			if text == "" {
+				continue
+			}
+			if msgMap[msgId] == nil {
+				msgMap[msgId] = make(map[string]string)
+			}
+			msgMap[msgId][form] = text
+		}
+	}
+	for i := range group.Groups {
+		collectXliffGroup(&group.Groups[i], useSource, msgMap, descriptions)
+	}
+}
+
+// ExportXliff writes messages of a source locale, with translations of a target locale, as a XLIFF file to w.
+// Translations are taken from the target locale only (without fallback/parent locales), and are empty if missing.
+//
+// Descriptions of messages are written as notes. Plural variants of a message are written as a group of units, one unit
+// per plural form, whose ids (XLIFF 1.2) or names (XLIFF 2.0) are "<msg-id>[<plural-form>]" (e.g. "files[one]"). Plural
+// forms are those of the source message and the CLDR plural forms of the target locale. Messages in ICU MessageFormat
+// (see Message.Syntax), with select variants (see Message.Variants) or with exact plural forms (see Message.Exact)
+// cannot be exported, an error is returned if there is any.
+//
+// Available since v0.3.0
+func ExportXliff(w io.Writer, i18n I18n, sourceLocale, targetLocale string, version XliffVersion) error {
+	if version != Xliff12 && version != Xliff20 {
+		return fmt.Errorf("XLIFF version [%s] is not supported", version)
+	}
+	sourceLocale, messages, err := exportMessages(i18n, sourceLocale)
+	if err != nil {
+		return err
+	}
+	targetLocale = canonicalLocale(targetLocale)
+	targetMessages := i18n.(*Goi18n).messagesStore[targetLocale]
+	_, targetCategories := gettextPluralForms(targetLocale)
+	exported := append(make([]*Message, 0, len(messages)*2), messages...)
+	for _, msg := range messages {
+		if target := targetMessages[msg.Id]; target != nil {
+			exported = append(exported, target)
+		}
+	}
+	if err := checkNoIcuMessages("a XLIFF file", exported); err != nil {
+		return err
+	}
+	if err := checkNoVariants("a XLIFF file", exported); err != nil {
+		return err
+	}
+	if err := checkNoExact("a XLIFF file", exported); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	if version == Xliff12 {
+		bw.WriteString(`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">` + "\n")
+		bw.WriteString(`  <file original="goyai" datatype="plaintext" source-language="` + xmlEscape(sourceLocale) + `" target-language="` + xmlEscape(targetLocale) + `">` + "\n")
+		bw.WriteString("    <body>\n")
+	} else {
+		bw.WriteString(`<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="` + xmlEscape(sourceLocale) + `" trgLang="` + xmlEscape(targetLocale) + `">` + "\n")
+		bw.WriteString(`  <file id="goyai">` + "\n")
+	}
+	indent := "    " // units are children of file (XLIFF 2.0) or body (XLIFF 1.2)
+	if version == Xliff12 {
+		indent = "      "
+	}
+	for i, msg := range messages {
+		target := targetMessages[msg.Id]
+		if target == nil {
+			target = &Message{}
+		}
+		if !msg.hasPluralForms() && !target.hasPluralForms() {
+			writeXliffUnit(bw, version, fmt.Sprintf("u%d", i+1), msg.Id, msg.Description, msg.Other, target.Other, indent)
+			continue
+		}
+		groupId := fmt.Sprintf("g%d", i+1)
+		if version == Xliff12 {
+			bw.WriteString(indent + `<group id="` + xmlEscape(msg.Id) + `" restype="x-gettext-plurals">` + "\n")
+			if msg.Description != "" {
+				bw.WriteString(indent + "  <note>" + xmlEscape(msg.Description) + "</note>\n")
+			}
+		} else {
+			bw.WriteString(indent + `<group id="` + groupId + `" name="` + xmlEscape(msg.Id) + `">` + "\n")
+			if msg.Description != "" {
+				bw.WriteString(indent + "  <notes>\n" + indent + "    <note>" + xmlEscape(msg.Description) + "</note>\n" + indent + "  </notes>\n")
+			}
+		}
+		for _, category := range pluralCategories {
+			if msg.pluralForm(category) == "" && target.pluralForm(category) == "" && !containsString(targetCategories, category) {
+				continue
+			}
+			source := msg.pluralForm(category)
+			if source == "" {
+				source = msg.Other
+			}
+			writeXliffUnit(bw, version, groupId+"-"+category, msg.Id+"["+category+"]", "", source, target.pluralForm(category), indent+"  ")
+		}
+		bw.WriteString(indent + "</group>\n")
+	}
+	if version == Xliff12 {
+		bw.WriteString("    </body>\n")
+	}
+	bw.WriteString("  </file>\n</xliff>\n")
+	return bw.Flush()
+}
+
+func writeXliffUnit(w *bufio.Writer, version XliffVersion, id, msgId, description, source, target, indent string) {
+	if version == Xliff12 {
+		w.WriteString(indent + `<trans-unit id="` + xmlEscape(msgId) + `">` + "\n")
+		w.WriteString(indent + "  <source>" + xmlEscape(source) + "</source>\n")
+		w.WriteString(indent + "  <target>" + xmlEscape(target) + "</target>\n")
+		if description != "" {
+			w.WriteString(indent + "  <note>" + xmlEscape(description) + "</note>\n")
+		}
+		w.WriteString(indent + "</trans-unit>\n")
+		return
+	}
+	w.WriteString(indent + `<unit id="` + id + `" name="` + xmlEscape(msgId) + `">` + "\n")
+	if description != "" {
+		w.WriteString(indent + "  <notes>\n" + indent + "    <note>" + xmlEscape(description) + "</note>\n" + indent + "  </notes>\n")
+	}
+	w.WriteString(indent + "  <segment>\n")
+	w.WriteString(indent + "    <source>" + xmlEscape(source) + "</source>\n")
+	w.WriteString(indent + "    <target>" + xmlEscape(target) + "</target>\n")
+	w.WriteString(indent + "  </segment>\n")
+	w.WriteString(indent + "</unit>\n")
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,245 @@
+package goyai
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const xliff12Content = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="app" datatype="plaintext" source-language="en" target-language="de">
+    <body>
+      <trans-unit id="hello">
+        <source>Hello {{.name}}</source>
+        <target>Hallo {{.name}}</target>
+        <note>Greeting message</note>
+      </trans-unit>
+      <trans-unit id="untranslated">
+        <source>Untranslated</source>
+        <target></target>
+      </trans-unit>
+      <group id="files" restype="x-gettext-plurals">
+        <note>Number of files</note>
+        <trans-unit id="files[one]">
+          <source>{{.n}} file</source>
+          <target>{{.n}} Datei</target>
+        </trans-unit>
+        <trans-unit id="files[other]">
+          <source>{{.n}} files</source>
+          <target>{{.n}} Dateien</target>
+        </trans-unit>
+      </group>
+    </body>
+  </file>
+</xliff>`
+
+const xliff20Content = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="ru">
+  <file id="f1">
+    <unit id="u1" name="hello">
+      <notes><note>Greeting message</note></notes>
+      <segment>
+        <source>Hello {{.name}}</source>
+        <target>Привет, {{.name}}</target>
+      </segment>
+    </unit>
+    <group id="g1" name="files">
+      <unit id="g1-one" name="files[one]"><segment><source>{{.n}} file</source><target>{{.n}} файл</target></segment></unit>
+      <unit id="g1-few" name="files[few]"><segment><source>{{.n}} files</source><target>{{.n}} файла</target></segment></unit>
+      <unit id="g1-many" name="files[many]"><segment><source>{{.n}} files</source><target>{{.n}} файлов</target></segment></unit>
+      <unit id="g1-other" name="files[other]"><segment><source>{{.n}} files</source><target>{{.n}} файла</target></segment></unit>
+    </group>
+  </file>
+</xliff>`
+
+func TestBuildI18n_Xliff(t *testing.T) {
+	testName := "TestBuildI18n_Xliff"
+	testCases := []struct {
+		name, content, locale string
+		expected              map[interface{}]string
+	}{
+		{"1.2", xliff12Content, "de", map[interface{}]string{1: "1 Datei", 2: "2 Dateien"}},
+		{"2.0", xliff20Content, "ru", map[interface{}]string{1: "1 файл", 3: "3 файла", 5: "5 файлов"}},
+	}
+	for _, tc := range testCases {
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{DefaultLocale: tc.locale})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.name, err)
+		}
+		if e, v := 1, len(i18n.AvailableLocales()); v != e {
+			t.Fatalf("%s failed for [%s]: expected %d available locales but received %d", testName, tc.name, e, v)
+		}
+		if v := i18n.Localize(tc.locale, "hello", "X"); !strings.HasSuffix(v, " X") || strings.HasPrefix(v, "Hello ") {
+			t.Fatalf("%s failed for [%s]: received %#v", testName, tc.name, v)
+		}
+		if v := i18n.Localize(tc.locale, "untranslated"); v != "" {
+			t.Fatalf("%s failed for [%s]: expected empty message but received %#v", testName, tc.name, v)
+		}
+		for count, e := range tc.expected {
+			cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"n": count}}
+			if v := i18n.Localize(tc.locale, "files", cfg); v != e {
+				t.Fatalf("%s failed for [%s/%v]: expected %#v but received %#v", testName, tc.name, count, e, v)
+			}
+		}
+		if e, v := "Greeting message", i18n.(*Goi18n).messagesStore[tc.locale]["hello"].Description; v != e {
+			t.Fatalf("%s failed for [%s]: expected description %#v but received %#v", testName, tc.name, e, v)
+		}
+	}
+
+	// source-only file
+	content := strings.ReplaceAll(xliff12Content, ` target-language="de"`, "")
+	i18n, err := BuildI18nFromBytes([]byte(content), I18nOptions{I18nFileFormat: Xliff})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if e, v := "Untranslated", i18n.Localize("en", "untranslated"); v != e {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, e, v)
+	}
+
+	// inline elements
+	xliff12Inline := `<xliff version="1.2"><file source-language="en" target-language="vi"><body><trans-unit id="hello"><source>Hello</source>` +
+		`<target>Xin chao <x id="1"%s/> the gioi <g id="2">dam <mrk mtype="term">dac</mrk></g><bx id="3"/>!</target></trans-unit></body></file></xliff>`
+	xliff20Inline := `<xliff version="2.0" srcLang="en" trgLang="vi"><file id="f1"><unit id="u1" name="hello"><segment><source>Hello</source>` +
+		`<target>Xin chao <ph id="1"%s/> the gioi <pc id="2">dam</pc><sc id="3"/>!<ec startRef="3"/></target></segment></unit></file></xliff>`
+	inlineCases := []struct {
+		name, content, expected string
+	}{
+		{"1.2", fmt.Sprintf(xliff12Inline, ` equiv-text="{{.name}}"`), "Xin chao X the gioi dam dac!"},
+		{"2.0", fmt.Sprintf(xliff20Inline, ` equiv="{{.name}}"`), "Xin chao X the gioi dam!"},
+	}
+	for _, tc := range inlineCases {
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{I18nFileFormat: Xliff})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.name, err)
+		}
+		if v := i18n.Localize("vi", "hello", "X"); v != tc.expected {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.name, tc.expected, v)
+		}
+	}
+	for _, content := range []string{fmt.Sprintf(xliff12Inline, ""), fmt.Sprintf(xliff20Inline, "")} {
+		if _, err := BuildI18nFromBytes([]byte(content), I18nOptions{I18nFileFormat: Xliff}); err == nil || !strings.Contains(err.Error(), "no text equivalent") {
+			t.Fatalf("%s failed: expected error for placeholder element without text equivalent but received %#v", testName, err)
+		}
+	}
+
+	// languages are taken from the attributes of the document's version only
+	mixedCases := []struct {
+		name, content, locale string
+	}{
+		{"1.2", `<xliff version="1.2" srcLang="fr" trgLang="de"><file source-language="en" target-language="vi"><body>` +
+			`<trans-unit id="hello"><source>Hello</source><target>Xin chao</target></trans-unit></body></file></xliff>`, "vi"},
+		{"2.0", `<xliff version="2.0" srcLang="en" trgLang="vi"><file id="f1" source-language="fr" target-language="de">` +
+			`<unit id="hello"><segment><source>Hello</source><target>Xin chao</target></segment></unit></file></xliff>`, "vi"},
+		{"2.0-source", `<xliff version="2.0" srcLang="en"><file id="f1" target-language="vi">` +
+			`<unit id="hello"><segment><source>Xin chao</source></segment></unit></file></xliff>`, "en"},
+	}
+	for _, tc := range mixedCases {
+		i18n, err := BuildI18nFromBytes([]byte(tc.content), I18nOptions{I18nFileFormat: Xliff})
+		if i18n == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, tc.name, err)
+		}
+		if locales := i18n.AvailableLocales(); len(locales) != 1 || locales[0].Id != tc.locale {
+			t.Fatalf("%s failed for [%s]: expected locale %#v but received %#v", testName, tc.name, tc.locale, locales)
+		}
+		if e, v := "Xin chao", i18n.Localize(tc.locale, "hello"); v != e {
+			t.Fatalf("%s failed for [%s]: expected %#v but received %#v", testName, tc.name, e, v)
+		}
+	}
+	for _, content := range []string{
+		`<xliff version="1.2" srcLang="en" trgLang="vi"><file><body><trans-unit id="hello"><source>Hello</source></trans-unit></body></file></xliff>`,
+		`<xliff version="2.0"><file id="f1" source-language="en"><unit id="hello"><segment><source>Hello</source></segment></unit></file></xliff>`,
+	} {
+		if _, err := BuildI18nFromBytes([]byte(content), I18nOptions{I18nFileFormat: Xliff}); err == nil {
+			t.Fatalf("%s failed: expected error for file without source and target language\n%s", testName, content)
+		}
+	}
+
+	if _, err := BuildI18nFromBytes([]byte("<xliff><file>"), I18nOptions{I18nFileFormat: Xliff}); err == nil {
+		t.Fatalf("%s failed: expected error for invalid XML", testName)
+	}
+}
+
+func TestExportXliff(t *testing.T) {
+	testName := "TestExportXliff"
+	yamlContentXliff := `
+en:
+  hello:
+    desc: Greeting message
+    other: Hello <b>{{.name}}</b> & "friends"
+  bye: Bye
+  files:
+    one: "{{.n}} file"
+    other: "{{.n}} files"
+ru:
+  hello: Привет, <b>{{.name}}</b>
+  files:
+    one: "{{.n}} файл"
+    few: "{{.n}} файла"
+    many: "{{.n}} файлов"
+    other: "{{.n}} файла"
+`
+	i18n, err := BuildI18nFromBytes([]byte(yamlContentXliff), I18nOptions{DefaultLocale: "en"})
+	if i18n == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for _, version := range []XliffVersion{Xliff12, Xliff20} {
+		buf := new(bytes.Buffer)
+		if err := ExportXliff(buf, i18n, "en", "ru", version); err != nil {
+			t.Fatalf("%s failed for [%s]: %s", testName, version, err)
+		}
+		if !strings.Contains(buf.String(), "files[many]") {
+			t.Fatalf("%s failed for [%s]: expected plural form 'many' of target locale\n%s", testName, version, buf.String())
+		}
+		exported, err := BuildI18nFromBytes(buf.Bytes(), I18nOptions{})
+		if exported == nil || err != nil {
+			t.Fatalf("%s failed for [%s]: %s\n%s", testName, version, err, buf.String())
+		}
+		if e, v := 1, len(exported.AvailableLocales()); v != e {
+			t.Fatalf("%s failed for [%s]: expected %d available locales but received %d", testName, version, e, v)
+		}
+		for msgId := range i18n.(*Goi18n).messagesStore["ru"] {
+			for _, count := range []interface{}{nil, 1, 2, 5, "1.5"} {
+				cfg := LocalizeConfig{PluralCount: count, TemplateData: map[string]interface{}{"name": "X", "n": count}}
+				if e, v := i18n.Localize("ru", msgId, cfg), exported.Localize("ru", msgId, cfg); v != e {
+					t.Fatalf("%s failed for [%s/%s/%v]: expected %#v but received %#v", testName, version, msgId, count, e, v)
+				}
+			}
+		}
+		if v := exported.Localize("ru", "bye"); v != "" {
+			t.Fatalf("%s failed for [%s]: expected empty translation but received %#v", testName, version, v)
+		}
+	}
+
+	if err := ExportXliff(new(bytes.Buffer), i18n, "en", "ru", XliffVersion("3.0")); err == nil {
+		t.Fatalf("%s failed: expected error for unsupported version", testName)
+	}
+	if err := ExportXliff(new(bytes.Buffer), i18n, "fr", "ru", Xliff12); err == nil {
+		t.Fatalf("%s failed: expected error for non-exist source locale", testName)
+	}
+
+	icu, err := BuildI18nFromBytes([]byte("en:\n  items: \"{{.count}} items\"\nru:\n  _syntax: icu\n  items: \"{count, plural, one {# файл} other {# файлов}}\"\n"), I18nOptions{DefaultLocale: "en"})
+	if icu == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportXliff(new(bytes.Buffer), icu, "en", "ru", Xliff12); err == nil {
+		t.Fatalf("%s failed: expected error for message in ICU MessageFormat", testName)
+	}
+
+	variants, err := BuildI18nFromBytes([]byte("en:\n  hello: Hello\nru:\n  hello:\n    other: Привет\n    select:\n      female: Здравствуйте\n"), I18nOptions{DefaultLocale: "en"})
+	if variants == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportXliff(new(bytes.Buffer), variants, "en", "ru", Xliff20); err == nil {
+		t.Fatalf("%s failed: expected error for message with select variants", testName)
+	}
+
+	exact, err := BuildI18nFromBytes([]byte("en:\n  files:\n    \"=0\": No files\n    one: One file\n    other: \"{{.count}} files\"\n"), I18nOptions{DefaultLocale: "en"})
+	if exact == nil || err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if err := ExportXliff(new(bytes.Buffer), exact, "en", "en", Xliff12); err == nil {
+		t.Fatalf("%s failed: expected error for message with exact plural forms", testName)
+	}
+}